@@ -0,0 +1,319 @@
+package GeTuiGo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// 别名同步的冲突原因
+const (
+	AliasConflictTooManyCids = "too_many_cids" // 别名要绑定的cid超过10个，超出的cid不绑定
+	AliasConflictMultiAlias  = "multi_alias"   // 同一个cid要绑定多个别名，该cid不做任何处理
+)
+
+// 别名同步中无法按期望处理的映射
+type AliasConflict struct {
+	Reason  string   // 冲突原因，AliasConflictXXX
+	Alias   string   // too_many_cids时为超出限制的别名
+	Cids    []string // too_many_cids时为没有绑定的cid
+	Cid     string   // multi_alias时为冲突的cid
+	Aliases []string // multi_alias时为该cid要绑定的别名
+}
+
+// SyncAlias 的设置，nil时使用默认值
+type AliasSyncOptions struct {
+	Concurrency int  // 同时查询或解绑的请求数，默认4
+	DryRun      bool // 只查询并计算差异，不绑定和解绑
+}
+
+// SyncAlias 的结果
+type AliasSyncResult struct {
+	Bind      []Alias           // 需要绑定的cid
+	Unbind    []Alias           // 需要单独解绑的cid
+	UnbindAll []string          // 需要解绑所有cid的别名
+	Moved     map[string]string // 绑定前已属于其他别名的cid -> 原别名，绑定时自动与原别名解绑
+	Unchanged int               // 已经按期望绑定的cid数
+	Conflicts []AliasConflict   // 冲突，按别名排序
+	Failures  []error           // 执行失败的请求，DryRun时为空
+}
+
+// 将别名绑定关系同步为期望的映射，只发送必要的绑定和解绑请求
+//  desired	别名 -> cid列表，cid列表为空表示解绑该别名的所有cid，没有列出的别名不做处理
+//  opts	同步设置，可以为nil
+//
+//  先用 QueryCid 查询每个别名当前的cid，用 QueryAlias 查询要绑定的cid当前所属的别名，再计算差异：
+//  期望之外的cid解绑，绑定到其他别名的cid直接绑定（自动与原别名解绑），
+//  一个别名最多10个cid，超出时优先保留已绑定的cid，其余记为冲突；同一个cid对应多个别名时记为冲突且不处理，
+//  已绑定的这类cid同样占用别名的数量。
+//  查询失败时不做任何修改并返回错误；执行时先解绑再分批绑定，失败的请求记录在result.Failures中，err为第一个失败
+func (c *Client) SyncAlias(desired map[string][]string, opts *AliasSyncOptions) (result AliasSyncResult, err error) {
+	return c.SyncAliasContext(context.Background(), desired, opts)
+}
+
+// 同 SyncAlias，ctx可用于取消请求或设置超时
+func (c *Client) SyncAliasContext(ctx context.Context, desired map[string][]string, opts *AliasSyncOptions) (result AliasSyncResult, err error) {
+	var o AliasSyncOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	aliases := make([]string, 0, len(desired))
+	for alias := range desired {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var v validator
+	for _, alias := range aliases {
+		v.check(alias != "", "alias", "is required")
+		for _, cid := range desired[alias] {
+			v.check(cid != "", "cid", "is required for alias %s", alias)
+		}
+	}
+	if err = v.err(); err != nil {
+		return
+	}
+
+	want, conflicts := normalizeAliasMapping(aliases, desired)
+	result.Conflicts = conflicts
+
+	// 查询别名当前的cid
+	current := make([][]string, len(aliases))
+	errs := runChunks(ctx, len(aliases), o.Concurrency, func(i int) (err error) {
+		if _, current[i], err = c.QueryCidContext(ctx, aliases[i]); errors.Is(err, ErrAliasNotBind) {
+			current[i], err = nil, nil
+		}
+		return
+	})
+	if err = firstError(errs); err != nil {
+		return
+	}
+
+	skipped := make(map[string]bool) // 对应多个别名的cid
+	for _, conflict := range conflicts {
+		skipped[conflict.Cid] = true
+	}
+
+	// 期望的 cid -> 别名，超出数量限制的cid不绑定；对应多个别名但已绑定的cid保持绑定，同样占用数量
+	target := make(map[string]string)
+	var tooMany []AliasConflict
+	for i, alias := range aliases {
+		limit := maxAliasCids
+		for _, cid := range current[i] {
+			if skipped[cid] {
+				limit--
+			}
+		}
+		var dropped []string
+		want[i], dropped = limitAliasCids(want[i], current[i], limit)
+		if len(dropped) > 0 {
+			tooMany = append(tooMany, AliasConflict{Reason: AliasConflictTooManyCids, Alias: alias, Cids: dropped})
+		}
+		for _, cid := range want[i] {
+			target[cid] = alias
+		}
+	}
+	result.Conflicts = append(result.Conflicts, tooMany...)
+	sort.SliceStable(result.Conflicts, func(i, j int) bool {
+		return conflictAlias(result.Conflicts[i]) < conflictAlias(result.Conflicts[j])
+	})
+	// 计算差异
+	boundTo := make(map[string]string) // 已查询到的 cid -> 当前别名
+	for i, alias := range aliases {
+		for _, cid := range current[i] {
+			boundTo[cid] = alias
+		}
+	}
+	for i, alias := range aliases {
+		if len(desired[alias]) == 0 {
+			if len(current[i]) > 0 {
+				result.UnbindAll = append(result.UnbindAll, alias)
+			}
+			continue
+		}
+
+		bound := stringSet(current[i])
+		var add []string
+		for _, cid := range want[i] {
+			if bound[cid] {
+				result.Unchanged++
+			} else {
+				add = append(add, cid)
+			}
+		}
+
+		// 期望之外的cid：要绑定到其他别名的在绑定时自动解绑，其余解绑；
+		// 数量可能超过限制时全部先解绑
+		wanted := stringSet(want[i])
+		var stale, moving []string
+		for _, cid := range current[i] {
+			switch {
+			case wanted[cid] || skipped[cid]:
+			case target[cid] != "":
+				moving = append(moving, cid)
+			default:
+				stale = append(stale, cid)
+			}
+		}
+		if len(current[i])-len(stale)+len(add) > maxAliasCids {
+			stale = append(stale, moving...)
+		}
+		for _, cid := range stale {
+			result.Unbind = append(result.Unbind, Alias{Cid: cid, Alias: alias})
+		}
+		for _, cid := range add {
+			result.Bind = append(result.Bind, Alias{Cid: cid, Alias: alias})
+		}
+	}
+
+	// 查询要绑定的cid当前所属的别名
+	var unknown []string
+	for _, item := range result.Bind {
+		if from, ok := boundTo[item.Cid]; ok {
+			result.addMoved(item.Cid, from)
+		} else {
+			unknown = append(unknown, item.Cid)
+		}
+	}
+	from := make([]string, len(unknown))
+	errs = runChunks(ctx, len(unknown), o.Concurrency, func(i int) (err error) {
+		if _, from[i], err = c.QueryAliasContext(ctx, unknown[i]); errors.Is(err, ErrAliasNotBind) {
+			from[i], err = "", nil
+		}
+		return
+	})
+	if err = firstError(errs); err != nil {
+		return
+	}
+	for i, cid := range unknown {
+		if from[i] != "" {
+			result.addMoved(cid, from[i])
+		}
+	}
+
+	if o.DryRun {
+		return
+	}
+	return result, c.applyAliasSync(ctx, &result, o.Concurrency)
+}
+
+// 按 UnbindAll、Unbind、Bind 的顺序执行同步
+func (c *Client) applyAliasSync(ctx context.Context, result *AliasSyncResult, concurrency int) error {
+	errs := runChunks(ctx, len(result.UnbindAll), concurrency, func(i int) error {
+		if _, _, err := c.UnBindAliasAllContext(ctx, result.UnbindAll[i]); err != nil {
+			return fmt.Errorf("getui: unbind all cids of alias %s: %w", result.UnbindAll[i], err)
+		}
+		return nil
+	})
+	errs = append(errs, runChunks(ctx, len(result.Unbind), concurrency, func(i int) error {
+		item := result.Unbind[i]
+		if _, err := c.UnBindAliasContext(ctx, item.Cid, item.Alias); err != nil {
+			return fmt.Errorf("getui: unbind cid %s from alias %s: %w", item.Cid, item.Alias, err)
+		}
+		return nil
+	})...)
+	if len(result.Bind) > 0 {
+		_, _, err := c.BindAliasContext(ctx, result.Bind)
+		errs = append(errs, err)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			result.Failures = append(result.Failures, err)
+		}
+	}
+	return firstError(result.Failures)
+}
+
+func (r *AliasSyncResult) addMoved(cid, from string) {
+	if r.Moved == nil {
+		r.Moved = make(map[string]string)
+	}
+	r.Moved[cid] = from
+}
+
+// 去掉重复和对应多个别名的cid，返回每个别名的cid列表和冲突
+func normalizeAliasMapping(aliases []string, desired map[string][]string) (want [][]string, conflicts []AliasConflict) {
+	cidAliases := make(map[string][]string)
+	var cids []string
+	want = make([][]string, len(aliases))
+	for i, alias := range aliases {
+		seen := make(map[string]bool)
+		for _, cid := range desired[alias] {
+			if seen[cid] {
+				continue
+			}
+			seen[cid] = true
+			want[i] = append(want[i], cid)
+			if len(cidAliases[cid]) == 0 {
+				cids = append(cids, cid)
+			}
+			cidAliases[cid] = append(cidAliases[cid], alias)
+		}
+	}
+
+	for _, cid := range cids {
+		if len(cidAliases[cid]) > 1 {
+			conflicts = append(conflicts, AliasConflict{Reason: AliasConflictMultiAlias, Cid: cid, Aliases: cidAliases[cid]})
+		}
+	}
+	for i := range want {
+		kept := want[i][:0]
+		for _, cid := range want[i] {
+			if len(cidAliases[cid]) == 1 {
+				kept = append(kept, cid)
+			}
+		}
+		want[i] = kept
+	}
+	return
+}
+
+// 别名最多保留limit个cid，优先保留已绑定的，其余按原顺序
+func limitAliasCids(want, current []string, limit int) (kept, dropped []string) {
+	if len(want) <= limit {
+		return want, nil
+	}
+	bound := stringSet(current)
+	for _, first := range []bool{true, false} {
+		for _, cid := range want {
+			if bound[cid] == first && len(kept) < limit {
+				kept = append(kept, cid)
+			}
+		}
+	}
+	keptSet := stringSet(kept)
+	for _, cid := range want {
+		if !keptSet[cid] {
+			dropped = append(dropped, cid)
+		}
+	}
+	return
+}
+
+// 冲突排序用的别名，multi_alias使用第一个别名
+func conflictAlias(conflict AliasConflict) string {
+	if conflict.Alias != "" {
+		return conflict.Alias
+	}
+	return conflict.Aliases[0]
+}
+
+func stringSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, s := range list {
+		set[s] = true
+	}
+	return set
+}
+
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
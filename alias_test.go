@@ -0,0 +1,219 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/litinghong/GeTuiGoClient/getuitest"
+)
+
+func sortedAliasCids(alias string) []string {
+	cids := fakeServer.AliasCids(alias)
+	sort.Strings(cids)
+	return cids
+}
+
+func TestClient_BindAlia(t *testing.T) {
+	client := getClient(t)
+
+	if _, _, err := client.BindAlia("bind_alia", "bind-alia-cid1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.BindAlia("bind_alia", "bind-alia-cid2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, cids, err := client.QueryCid("bind_alia"); err != nil || !reflect.DeepEqual(cids, []string{"bind-alia-cid1", "bind-alia-cid2"}) {
+		t.Fatalf("unexpected cids %v %v", cids, err)
+	}
+
+	if _, err := client.UnBindAlias("bind-alia-cid1", "bind_alia"); err != nil {
+		t.Fatal(err)
+	}
+	if cids := fakeServer.AliasCids("bind_alia"); !reflect.DeepEqual(cids, []string{"bind-alia-cid2"}) {
+		t.Fatalf("unexpected cids %v", cids)
+	}
+	if _, _, err := client.UnBindAliasAll("bind_alia"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.QueryCid("bind_alia"); !errors.Is(err, ErrAliasNotBind) {
+		t.Fatalf("expected ErrAliasNotBind, got %v", err)
+	}
+
+	if _, _, err := client.BindAlia("", "bind-alia-cid1"); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected ErrInvalidParam, got %v", err)
+	}
+}
+
+func TestClient_BindAlias_Chunks(t *testing.T) {
+	client := getClient(t)
+
+	aliasList := make([]Alias, 2500)
+	for i := range aliasList {
+		aliasList[i] = Alias{Cid: fmt.Sprintf("bind-chunk-cid%04d", i), Alias: fmt.Sprintf("bind_chunk_%04d", i)}
+	}
+	before := fakeServer.RequestCount("bind_alias")
+	if _, _, err := client.BindAlias(aliasList); err != nil {
+		t.Fatal(err)
+	}
+	if n := fakeServer.RequestCount("bind_alias") - before; n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+	if cids := fakeServer.AliasCids("bind_chunk_2499"); !reflect.DeepEqual(cids, []string{"bind-chunk-cid2499"}) {
+		t.Fatalf("unexpected cids %v", cids)
+	}
+
+	fakeServer.Fail("bind_alias", getuitest.Failure{Result: ResultOtherError, Times: 1})
+	defer fakeServer.ClearFailures()
+	_, _, err := client.BindAlias(aliasList)
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Index != 0 || len(chunkErr.Targets) != 1000 {
+		t.Fatalf("expected first chunk error, got %v", err)
+	}
+}
+
+func TestClient_SyncAlias(t *testing.T) {
+	client := getClient(t)
+
+	var current []Alias
+	bind := func(alias string, cids ...string) {
+		for _, cid := range cids {
+			current = append(current, Alias{Cid: cid, Alias: alias})
+		}
+	}
+	bind("sync_a", "sync-a1", "sync-a2", "sync-a3", "sync-x1")
+	bind("sync_c", "sync-c1")
+	bind("sync_d", "sync-d11")
+	bind("sync_e", "sync-e1")
+	for i := 0; i < 10; i++ {
+		bind("sync_f", fmt.Sprintf("sync-f%d", i))
+	}
+	if _, _, err := client.BindAlias(current); err != nil {
+		t.Fatal(err)
+	}
+
+	desired := map[string][]string{
+		"sync_a": {"sync-a1", "sync-a4", "sync-x1", "sync-a1"},
+		"sync_b": {"sync-a3", "sync-e1", "sync-x1"},
+		"sync_c": {},
+		"sync_f": {"sync-f0", "sync-f1", "sync-f2", "sync-f3", "sync-f4", "sync-g0", "sync-g1", "sync-g2", "sync-g3", "sync-g4"},
+		"sync_g": {"sync-f5", "sync-f6", "sync-f7", "sync-f8", "sync-f9"},
+	}
+	for i := 0; i < 12; i++ {
+		desired["sync_d"] = append(desired["sync_d"], fmt.Sprintf("sync-d%d", i))
+	}
+
+	before := fakeServer.RequestCount("bind_alias")
+	result, err := client.SyncAlias(desired, &AliasSyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fakeServer.RequestCount("bind_alias") != before || len(fakeServer.AliasCids("sync_b")) != 0 {
+		t.Fatal("dry run should not change bindings")
+	}
+
+	wantUnbind := []Alias{{Cid: "sync-a2", Alias: "sync_a"}}
+	for i := 5; i < 10; i++ {
+		wantUnbind = append(wantUnbind, Alias{Cid: fmt.Sprintf("sync-f%d", i), Alias: "sync_f"})
+	}
+	if !reflect.DeepEqual(result.Unbind, wantUnbind) || !reflect.DeepEqual(result.UnbindAll, []string{"sync_c"}) {
+		t.Fatalf("unexpected unbind %v %v", result.Unbind, result.UnbindAll)
+	}
+	if len(result.Bind) != 1+2+9+5+5 || result.Bind[0] != (Alias{Cid: "sync-a4", Alias: "sync_a"}) {
+		t.Fatalf("unexpected bind %v", result.Bind)
+	}
+	if result.Unchanged != 1+1+5 {
+		t.Fatalf("unexpected unchanged %d", result.Unchanged)
+	}
+	if result.Moved["sync-a3"] != "sync_a" || result.Moved["sync-e1"] != "sync_e" || result.Moved["sync-f5"] != "sync_f" || len(result.Moved) != 7 {
+		t.Fatalf("unexpected moved %v", result.Moved)
+	}
+	wantConflicts := []AliasConflict{
+		{Reason: AliasConflictMultiAlias, Cid: "sync-x1", Aliases: []string{"sync_a", "sync_b"}},
+		{Reason: AliasConflictTooManyCids, Alias: "sync_d", Cids: []string{"sync-d9", "sync-d10"}},
+	}
+	if !reflect.DeepEqual(result.Conflicts, wantConflicts) {
+		t.Fatalf("unexpected conflicts %+v", result.Conflicts)
+	}
+
+	if result, err = client.SyncAlias(desired, nil); err != nil || len(result.Failures) != 0 {
+		t.Fatal(err, result.Failures)
+	}
+	wantCids := map[string][]string{
+		"sync_a": {"sync-a1", "sync-a4", "sync-x1"},
+		"sync_b": {"sync-a3", "sync-e1"},
+		"sync_c": nil,
+		"sync_d": {"sync-d0", "sync-d1", "sync-d11", "sync-d2", "sync-d3", "sync-d4", "sync-d5", "sync-d6", "sync-d7", "sync-d8"},
+		"sync_e": nil,
+		"sync_f": {"sync-f0", "sync-f1", "sync-f2", "sync-f3", "sync-f4", "sync-g0", "sync-g1", "sync-g2", "sync-g3", "sync-g4"},
+		"sync_g": {"sync-f5", "sync-f6", "sync-f7", "sync-f8", "sync-f9"},
+	}
+	for alias, cids := range wantCids {
+		if got := sortedAliasCids(alias); !reflect.DeepEqual(got, cids) {
+			t.Errorf("alias %s: got %v, want %v", alias, got, cids)
+		}
+	}
+
+	// 再次同步时没有需要修改的绑定
+	if result, err = client.SyncAlias(desired, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Bind)+len(result.Unbind)+len(result.UnbindAll) != 0 || result.Unchanged != 2+2+10+10+5 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_SyncAlias_QueryError(t *testing.T) {
+	client := getClient(t)
+
+	fakeServer.Fail("query_cid", getuitest.Failure{Result: ResultOtherError, Times: 1})
+	defer fakeServer.ClearFailures()
+
+	before := fakeServer.RequestCount("bind_alias")
+	if _, err := client.SyncAlias(map[string][]string{"sync_err": {"sync-err1"}}, nil); !errors.Is(err, ErrOtherError) {
+		t.Fatalf("expected ErrOtherError, got %v", err)
+	}
+	if fakeServer.RequestCount("bind_alias") != before {
+		t.Fatal("should not bind after query error")
+	}
+
+	if _, err := client.SyncAlias(map[string][]string{"sync_err": {""}}, nil); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected ErrInvalidParam, got %v", err)
+	}
+}
+
+func TestClient_SyncAlias_ConflictLimit(t *testing.T) {
+	client := getClient(t)
+
+	// sync-hx对应两个别名，不做处理，仍占用sync_h的一个位置
+	if _, _, err := client.BindAlia("sync_h", "sync-hx"); err != nil {
+		t.Fatal(err)
+	}
+	desired := map[string][]string{
+		"sync_h": {"sync-hx"},
+		"sync_i": {"sync-hx"},
+	}
+	for i := 0; i < 10; i++ {
+		desired["sync_h"] = append(desired["sync_h"], fmt.Sprintf("sync-h%d", i))
+	}
+
+	result, err := client.SyncAlias(desired, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantConflicts := []AliasConflict{
+		{Reason: AliasConflictMultiAlias, Cid: "sync-hx", Aliases: []string{"sync_h", "sync_i"}},
+		{Reason: AliasConflictTooManyCids, Alias: "sync_h", Cids: []string{"sync-h9"}},
+	}
+	if !reflect.DeepEqual(result.Conflicts, wantConflicts) {
+		t.Fatalf("unexpected conflicts %+v", result.Conflicts)
+	}
+	if len(result.Bind) != 9 || len(result.Failures) != 0 {
+		t.Fatalf("unexpected bind %v, failures %v", result.Bind, result.Failures)
+	}
+	if cids := fakeServer.AliasCids("sync_h"); len(cids) != maxAliasCids || cids[0] != "sync-hx" {
+		t.Fatalf("unexpected cids %v", cids)
+	}
+}
@@ -0,0 +1,308 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// apns推送消息序列化后的最大长度，单位字节
+const maxApnsPayload = 4096
+
+// 多媒体资源最多个数
+const maxApnsMultimedia = 3
+
+// 通知的中断级别，iOS 15及以上生效
+const (
+	InterruptionPassive       = "passive"        // 不亮屏、不发声
+	InterruptionActive        = "active"         // 默认级别
+	InterruptionTimeSensitive = "time-sensitive" // 时效性通知，可以突破专注模式
+	InterruptionCritical      = "critical"       // 重要警告，需要苹果授权
+)
+
+// 多媒体资源类型
+const (
+	MultimediaImage = 1 // 图片
+	MultimediaAudio = 2 // 音频
+	MultimediaVideo = 3 // 视频
+)
+
+// apns推送消息, json串，当手机为ios，并且为离线的时候
+//  可以使用 NewApns 构造，序列化后不能超过4KB
+type ApnPushInfo struct {
+	Aps        Aps          `json:"aps"`
+	Payload    string       `json:"payload,omitempty"`    // 增加自定义的数据
+	Multimedia []Multimedia `json:"multimedia,omitempty"` // 多媒体资源，最多3个，需要设置 Aps.MutableContent 为1
+}
+
+// apns的aps字段
+type Aps struct {
+	Alert             *ApsAlert `json:"alert,omitempty"`
+	Badge             *int      `json:"badge,omitempty"`              // 角标数字，0为清除角标，与AutoBadge二选一
+	AutoBadge         string    `json:"auto_badge,omitempty"`         // 角标增量，如"+1"
+	Sound             *ApsSound `json:"sound,omitempty"`              // 通知声音
+	ContentAvailable  int       `json:"content-available,omitempty"`  // 1表示静默推送
+	MutableContent    int       `json:"mutable-content,omitempty"`    // 1表示允许通知扩展修改内容，多媒体消息必须为1
+	Category          string    `json:"category,omitempty"`           // 通知的操作分类
+	ThreadId          string    `json:"thread-id,omitempty"`          // 相同的id会分组展示
+	InterruptionLevel string    `json:"interruption-level,omitempty"` // 使用 InterruptionXXX 常量
+	RelevanceScore    float64   `json:"relevance-score,omitempty"`    // 通知摘要中的排序权重，0~1
+}
+
+// apns通知内容，loc开头的字段为客户端本地化字符串的key和参数
+type ApsAlert struct {
+	Title           string   `json:"title,omitempty"`
+	Subtitle        string   `json:"subtitle,omitempty"`
+	Body            string   `json:"body,omitempty"`
+	LaunchImage     string   `json:"launch-image,omitempty"`
+	TitleLocKey     string   `json:"title-loc-key,omitempty"`
+	TitleLocArgs    []string `json:"title-loc-args,omitempty"`
+	SubtitleLocKey  string   `json:"subtitle-loc-key,omitempty"`
+	SubtitleLocArgs []string `json:"subtitle-loc-args,omitempty"`
+	LocKey          string   `json:"loc-key,omitempty"` // 通知内容的本地化key
+	LocArgs         []string `json:"loc-args,omitempty"`
+	ActionLocKey    string   `json:"action-loc-key,omitempty"`
+}
+
+// apns通知声音，普通声音序列化为文件名字符串，重要警告声音序列化为字典
+type ApsSound struct {
+	Critical int     `json:"critical"` // 1表示重要警告声音
+	Name     string  `json:"name"`     // 声音文件名，default为系统默认声音
+	Volume   float64 `json:"volume"`   // 重要警告的音量，0~1
+}
+
+func (s ApsSound) MarshalJSON() ([]byte, error) {
+	if s.Critical == 0 && s.Volume == 0 {
+		return json.Marshal(s.Name)
+	}
+	type plain ApsSound
+	return json.Marshal(plain(s))
+}
+
+// 兼容字符串与字典两种格式
+func (s *ApsSound) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*s = ApsSound{}
+		return json.Unmarshal(data, &s.Name)
+	}
+	type plain ApsSound
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// 多媒体资源
+type Multimedia struct {
+	Url      string `json:"url"`       // 资源地址
+	Type     int    `json:"type"`      // 资源类型，使用 MultimediaXXX 常量
+	OnlyWifi bool   `json:"only_wifi"` // 是否只在wifi下下载
+}
+
+// 检查apns推送消息，返回的错误为 ValidationErrors
+func (info *ApnPushInfo) Validate() error {
+	var v validator
+	v.nested("aps", info.Aps.validate())
+
+	v.check(len(info.Multimedia) <= maxApnsMultimedia, "multimedia", "must not contain more than %d items", maxApnsMultimedia)
+	for i, m := range info.Multimedia {
+		field := fmt.Sprintf("multimedia[%d]", i)
+		v.check(strings.HasPrefix(m.Url, "http://") || strings.HasPrefix(m.Url, "https://"), field+".url", "must be an http or https url")
+		v.check(m.Type >= MultimediaImage && m.Type <= MultimediaVideo, field+".type", "must be 1, 2 or 3")
+	}
+	if len(info.Multimedia) > 0 {
+		v.check(info.Aps.MutableContent == 1, "aps.mutable-content", "must be 1 when multimedia is set")
+	}
+
+	// 格式有误时不再检查长度
+	if len(v.errs) == 0 {
+		data, err := json.Marshal(info)
+		if err != nil {
+			v.add("", "%v", err)
+		} else {
+			v.check(len(data) <= maxApnsPayload, "", "payload is %d bytes, must not exceed %d bytes", len(data), maxApnsPayload)
+		}
+	}
+	return v.err()
+}
+
+func (aps *Aps) validate() error {
+	var v validator
+	if a := aps.Alert; a != nil {
+		v.check(len(a.TitleLocArgs) == 0 || a.TitleLocKey != "", "alert.title-loc-key", "is required when title-loc-args is set")
+		v.check(len(a.SubtitleLocArgs) == 0 || a.SubtitleLocKey != "", "alert.subtitle-loc-key", "is required when subtitle-loc-args is set")
+		v.check(len(a.LocArgs) == 0 || a.LocKey != "", "alert.loc-key", "is required when loc-args is set")
+	}
+	v.check(aps.Badge == nil || *aps.Badge >= 0, "badge", "must not be negative")
+	v.check(aps.Badge == nil || aps.AutoBadge == "", "auto_badge", "badge and auto_badge are mutually exclusive")
+	if s := aps.Sound; s != nil {
+		v.required("sound.name", s.Name)
+		v.check(s.Critical == 0 || s.Critical == 1, "sound.critical", "must be 0 or 1")
+		v.check(s.Volume >= 0 && s.Volume <= 1, "sound.volume", "must be between 0 and 1")
+	}
+	v.check(aps.ContentAvailable == 0 || aps.ContentAvailable == 1, "content-available", "must be 0 or 1")
+	v.check(aps.MutableContent == 0 || aps.MutableContent == 1, "mutable-content", "must be 0 or 1")
+	switch aps.InterruptionLevel {
+	case "", InterruptionPassive, InterruptionActive, InterruptionTimeSensitive, InterruptionCritical:
+	default:
+		v.add("interruption-level", "unsupported level %q", aps.InterruptionLevel)
+	}
+	v.check(aps.RelevanceScore >= 0 && aps.RelevanceScore <= 1, "relevance-score", "must be between 0 and 1")
+	return v.err()
+}
+
+// apns推送消息构造器
+//  示例：NewApns().Title("标题").Body("内容").Badge(1).Sound("default").Build()
+type ApnsBuilder struct {
+	info  ApnPushInfo
+	alert *ApsAlert
+}
+
+// 创建apns推送消息构造器
+func NewApns() *ApnsBuilder {
+	return &ApnsBuilder{}
+}
+
+func (b *ApnsBuilder) getAlert() *ApsAlert {
+	if b.alert == nil {
+		b.alert = &ApsAlert{}
+	}
+	return b.alert
+}
+
+func (b *ApnsBuilder) Title(title string) *ApnsBuilder {
+	b.getAlert().Title = title
+	return b
+}
+
+func (b *ApnsBuilder) Subtitle(subtitle string) *ApnsBuilder {
+	b.getAlert().Subtitle = subtitle
+	return b
+}
+
+func (b *ApnsBuilder) Body(body string) *ApnsBuilder {
+	b.getAlert().Body = body
+	return b
+}
+
+func (b *ApnsBuilder) LaunchImage(image string) *ApnsBuilder {
+	b.getAlert().LaunchImage = image
+	return b
+}
+
+// 使用本地化字符串作为标题
+func (b *ApnsBuilder) TitleLoc(key string, args ...string) *ApnsBuilder {
+	alert := b.getAlert()
+	alert.TitleLocKey, alert.TitleLocArgs = key, args
+	return b
+}
+
+// 使用本地化字符串作为副标题
+func (b *ApnsBuilder) SubtitleLoc(key string, args ...string) *ApnsBuilder {
+	alert := b.getAlert()
+	alert.SubtitleLocKey, alert.SubtitleLocArgs = key, args
+	return b
+}
+
+// 使用本地化字符串作为通知内容
+func (b *ApnsBuilder) BodyLoc(key string, args ...string) *ApnsBuilder {
+	alert := b.getAlert()
+	alert.LocKey, alert.LocArgs = key, args
+	return b
+}
+
+// 使用本地化字符串作为操作按钮标题
+func (b *ApnsBuilder) ActionLoc(key string) *ApnsBuilder {
+	b.getAlert().ActionLocKey = key
+	return b
+}
+
+// 设置角标数字，0为清除角标
+func (b *ApnsBuilder) Badge(badge int) *ApnsBuilder {
+	b.info.Aps.Badge = &badge
+	return b
+}
+
+// 设置角标增量，如"+1"
+func (b *ApnsBuilder) AutoBadge(autoBadge string) *ApnsBuilder {
+	b.info.Aps.AutoBadge = autoBadge
+	return b
+}
+
+// 设置通知声音文件名，default为系统默认声音
+func (b *ApnsBuilder) Sound(name string) *ApnsBuilder {
+	b.info.Aps.Sound = &ApsSound{Name: name}
+	return b
+}
+
+// 设置重要警告声音，需要苹果授权
+//  volume	音量，0~1
+func (b *ApnsBuilder) CriticalSound(name string, volume float64) *ApnsBuilder {
+	b.info.Aps.Sound = &ApsSound{Critical: 1, Name: name, Volume: volume}
+	return b
+}
+
+// 静默推送，不展示通知
+func (b *ApnsBuilder) ContentAvailable() *ApnsBuilder {
+	b.info.Aps.ContentAvailable = 1
+	return b
+}
+
+// 允许通知扩展修改内容
+func (b *ApnsBuilder) MutableContent() *ApnsBuilder {
+	b.info.Aps.MutableContent = 1
+	return b
+}
+
+func (b *ApnsBuilder) Category(category string) *ApnsBuilder {
+	b.info.Aps.Category = category
+	return b
+}
+
+func (b *ApnsBuilder) ThreadId(threadId string) *ApnsBuilder {
+	b.info.Aps.ThreadId = threadId
+	return b
+}
+
+// 设置中断级别，使用 InterruptionXXX 常量
+func (b *ApnsBuilder) InterruptionLevel(level string) *ApnsBuilder {
+	b.info.Aps.InterruptionLevel = level
+	return b
+}
+
+// 设置通知摘要中的排序权重，0~1
+func (b *ApnsBuilder) RelevanceScore(score float64) *ApnsBuilder {
+	b.info.Aps.RelevanceScore = score
+	return b
+}
+
+// 增加自定义的数据
+func (b *ApnsBuilder) Payload(payload string) *ApnsBuilder {
+	b.info.Payload = payload
+	return b
+}
+
+// 添加多媒体资源，会同时设置mutable-content为1
+//  typ	资源类型，使用 MultimediaXXX 常量
+func (b *ApnsBuilder) Multimedia(typ int, url string, onlyWifi bool) *ApnsBuilder {
+	b.info.Multimedia = append(b.info.Multimedia, Multimedia{Url: url, Type: typ, OnlyWifi: onlyWifi})
+	b.info.Aps.MutableContent = 1
+	return b
+}
+
+// 生成apns推送消息，检查同 ApnPushInfo.Validate
+//  每次调用返回新的ApnPushInfo
+func (b *ApnsBuilder) Build() (*ApnPushInfo, error) {
+	info := b.info
+	if b.alert != nil {
+		alert := *b.alert
+		info.Aps.Alert = &alert
+	}
+	if b.info.Aps.Badge != nil {
+		badge := *b.info.Aps.Badge
+		info.Aps.Badge = &badge
+	}
+	info.Multimedia = append([]Multimedia(nil), b.info.Multimedia...)
+
+	if err := info.Validate(); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
@@ -0,0 +1,102 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApnsBuilder_Build(t *testing.T) {
+	info, err := NewApns().
+		Title("标题").
+		BodyLoc("MSG_FORMAT", "Alice", "3").
+		Badge(0).
+		CriticalSound("alarm.caf", 0.8).
+		ThreadId("chat-1").
+		InterruptionLevel(InterruptionTimeSensitive).
+		RelevanceScore(0.5).
+		Payload("payload").
+		Multimedia(MultimediaImage, "https://example.com/a.png", true).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"aps":{"alert":{"title":"标题","loc-key":"MSG_FORMAT","loc-args":["Alice","3"]},"badge":0,` +
+		`"sound":{"critical":1,"name":"alarm.caf","volume":0.8},"mutable-content":1,"thread-id":"chat-1",` +
+		`"interruption-level":"time-sensitive","relevance-score":0.5},"payload":"payload",` +
+		`"multimedia":[{"url":"https://example.com/a.png","type":1,"only_wifi":true}]}`
+	if string(data) != want {
+		t.Fatalf("unexpected json\n got: %s\nwant: %s", data, want)
+	}
+
+	var decoded ApnPushInfo
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, info) {
+		t.Fatalf("unexpected push info %+v", decoded)
+	}
+}
+
+func TestApsSound_JSON(t *testing.T) {
+	data, _ := json.Marshal(Aps{Sound: &ApsSound{Name: "default"}})
+	if string(data) != `{"sound":"default"}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+
+	var aps Aps
+	if err := json.Unmarshal(data, &aps); err != nil {
+		t.Fatal(err)
+	}
+	if aps.Sound == nil || aps.Sound.Name != "default" {
+		t.Fatalf("unexpected sound %+v", aps.Sound)
+	}
+}
+
+func TestApnPushInfo_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *ApnsBuilder
+		field   string
+	}{
+		{"loc args without key", NewApns().TitleLoc("", "a"), "aps.alert.title-loc-key"},
+		{"badge and auto badge", NewApns().Badge(1).AutoBadge("+1"), "aps.auto_badge"},
+		{"volume", NewApns().CriticalSound("alarm.caf", 2), "aps.sound.volume"},
+		{"interruption level", NewApns().InterruptionLevel("urgent"), "aps.interruption-level"},
+		{"relevance score", NewApns().RelevanceScore(1.5), "aps.relevance-score"},
+		{"multimedia url", NewApns().Multimedia(MultimediaVideo, "ftp://example.com/a.mp4", false), "multimedia[0].url"},
+		{"multimedia type", NewApns().Multimedia(4, "https://example.com/a.mp4", false), "multimedia[0].type"},
+		{"payload size", NewApns().Body("body").Payload(strings.Repeat("x", maxApnsPayload)), ""},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.builder.Build()
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("%s: expected validation errors, got %v", tt.name, err)
+			continue
+		}
+		if errs[0].Field != tt.field {
+			t.Errorf("%s: expected field %q, got %v", tt.name, tt.field, err)
+		}
+	}
+
+	// 多媒体资源需要允许通知扩展修改内容
+	info := &ApnPushInfo{Multimedia: []Multimedia{{Url: "https://example.com/a.png", Type: MultimediaImage}}}
+	if err := info.Validate(); err == nil || !strings.Contains(err.Error(), "aps.mutable-content") {
+		t.Fatalf("expected mutable-content error, got %v", err)
+	}
+
+	// 发送前检查push_info
+	push := &Push{Template: TmplTransmission{TransmissionContent: "apns"}, PushInfo: info}
+	if err := push.Validate(); err == nil || !strings.Contains(err.Error(), "push_info.aps.mutable-content") {
+		t.Fatalf("expected push_info error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package GeTuiGo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	tokenRefreshAhead = 5 * time.Minute // 在auth_token过期前多久开始刷新
+	tokenDefaultTTL   = 24 * time.Hour  // 服务端未返回过期时间时使用的有效期
+	tokenFetchTimeout = time.Minute     // 未设置客户端超时时间时，获取auth_token的超时时间
+)
+
+// 一次正在进行的auth_token获取，并发调用方共享结果
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// auth_token缓存，过期前自动刷新，并发安全
+type tokenCache struct {
+	mu       sync.Mutex
+	token    string
+	expireAt time.Time
+	call     *tokenCall
+	timeout  time.Duration // 获取auth_token的超时时间，0时使用tokenFetchTimeout
+	// 获取新的auth_token及其过期时间
+	fetch func(ctx context.Context) (token string, expireAt time.Time, err error)
+}
+
+// 返回可用的auth_token，即将过期时先刷新
+func (tc *tokenCache) get(ctx context.Context) (string, error) {
+	tc.mu.Lock()
+	if tc.token != "" && time.Now().Add(tokenRefreshAhead).Before(tc.expireAt) {
+		token := tc.token
+		tc.mu.Unlock()
+		return token, nil
+	}
+	return tc.refreshLocked(ctx)
+}
+
+// 服务端返回not_auth时强制刷新
+//  stale	被服务端拒绝的auth_token，若已被其他调用方刷新过则直接返回新的auth_token
+func (tc *tokenCache) refresh(ctx context.Context, stale string) (string, error) {
+	tc.mu.Lock()
+	if tc.call == nil && tc.token != "" && tc.token != stale {
+		token := tc.token
+		tc.mu.Unlock()
+		return token, nil
+	}
+	return tc.refreshLocked(ctx)
+}
+
+// 发起或等待正在进行的刷新，调用前需持有锁，返回前释放
+//  刷新在后台使用独立的ctx进行，不受某个调用方取消的影响；每个调用方的ctx结束时只是自己不再等待
+func (tc *tokenCache) refreshLocked(ctx context.Context) (string, error) {
+	call := tc.call
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		tc.call = call
+		go tc.fetchCall(call)
+	}
+	tc.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// 获取auth_token并通知所有等待的调用方
+func (tc *tokenCache) fetchCall(call *tokenCall) {
+	timeout := tc.timeout
+	if timeout <= 0 {
+		timeout = tokenFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	token, expireAt, err := tc.fetch(ctx)
+
+	tc.mu.Lock()
+	if err == nil {
+		tc.token = token
+		tc.expireAt = expireAt
+	}
+	tc.call = nil
+	tc.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+}
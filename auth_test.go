@@ -0,0 +1,139 @@
+package GeTuiGo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenCache_SharedRefresh(t *testing.T) {
+	var fetches int32
+	tc := &tokenCache{
+		fetch: func(ctx context.Context) (string, time.Time, error) {
+			n := atomic.AddInt32(&fetches, 1)
+			time.Sleep(10 * time.Millisecond)
+			return fmt.Sprintf("token%d", n), time.Now().Add(time.Hour), nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := tc.get(context.Background()); err != nil || token != "token1" {
+				t.Errorf("unexpected token %s, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Fatalf("expected 1 fetch, got %d", fetches)
+	}
+
+	// 已被刷新过的旧token不会再次触发刷新
+	tc.refresh(context.Background(), "token0")
+	if fetches != 1 {
+		t.Fatalf("expected 1 fetch, got %d", fetches)
+	}
+
+	if token, _ := tc.refresh(context.Background(), "token1"); token != "token2" {
+		t.Fatalf("unexpected token %s", token)
+	}
+}
+
+func TestTokenCache_LeaderCancelled(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var fetches int32
+	tc := &tokenCache{
+		fetch: func(ctx context.Context) (string, time.Time, error) {
+			atomic.AddInt32(&fetches, 1)
+			close(started)
+			<-release
+			if err := ctx.Err(); err != nil {
+				return "", time.Time{}, err
+			}
+			return "token", time.Now().Add(time.Hour), nil
+		},
+	}
+
+	// 发起刷新的调用方先取消，不影响仍在等待的调用方
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := tc.get(leaderCtx)
+		leaderErr <- err
+	}()
+	<-started
+
+	waiter := make(chan string, 1)
+	go func() {
+		token, err := tc.get(context.Background())
+		if err != nil {
+			t.Errorf("waiter got error %v", err)
+		}
+		waiter <- token
+	}()
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected leader to be cancelled, got %v", err)
+	}
+	close(release)
+	if token := <-waiter; token != "token" || atomic.LoadInt32(&fetches) != 1 {
+		t.Fatalf("unexpected token %q after %d fetches", token, fetches)
+	}
+}
+
+func TestTokenCache_RefreshBeforeExpire(t *testing.T) {
+	var fetches int32
+	tc := &tokenCache{
+		fetch: func(ctx context.Context) (string, time.Time, error) {
+			atomic.AddInt32(&fetches, 1)
+			return "token", time.Now().Add(tokenRefreshAhead / 2), nil
+		},
+	}
+
+	tc.get(context.Background())
+	tc.get(context.Background())
+	if fetches != 2 {
+		t.Fatalf("expected token to be refreshed, got %d fetches", fetches)
+	}
+}
+
+func TestClient_RequestWithAuth_NotAuth(t *testing.T) {
+	var auths, pushes int32
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/auth_sign") {
+			n := atomic.AddInt32(&auths, 1)
+			fmt.Fprintf(w, `{"result":"ok","expire_time":"%d","auth_token":"token%d"}`,
+				time.Now().Add(time.Hour).UnixNano()/int64(time.Millisecond), n)
+			return
+		}
+
+		atomic.AddInt32(&pushes, 1)
+		if r.Header.Get("authtoken") != "token2" {
+			w.Write([]byte(`{"result":"not_auth"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"ok","taskid":"task","status":"successed_online"}`))
+	})
+	defer server.Close()
+
+	result, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Result != ResultOk || auths != 2 || pushes != 2 {
+		t.Fatalf("unexpected result %v, auths %d, pushes %d", result, auths, pushes)
+	}
+}
@@ -0,0 +1,301 @@
+package GeTuiGo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// 分批发送时默认同时发送的批数
+const defaultBatchConcurrency = 4
+
+// PushListAll 的分批设置，nil时使用默认值
+type PushListOptions struct {
+	ChunkSize   int  // 每批的目标数，默认且最多1000
+	Concurrency int  // 同时发送的批数，默认4
+	NeedDetail  bool // 是否需要返回每个目标的状态
+	Alias       bool // 目标为别名列表，默认为cid列表
+}
+
+// 一批目标发送失败
+type ChunkError struct {
+	Index   int      // 批次序号，从0开始
+	Targets []string // 本批的目标
+	Err     error    // 失败原因
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("getui: chunk %d (%d targets): %v", e.Index, len(e.Targets), e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// PushListAll 的汇总结果
+type PushListAllResult struct {
+	TaskId       string            // 任务号
+	Chunks       int               // 总批数
+	Sent         int               // 发送成功的目标数
+	CidDetails   map[string]string // 所有成功批次的cid推送结果详情，需要设置NeedDetail
+	AliasDetails map[string]string // 所有成功批次的别名推送结果详情，需要设置NeedDetail
+	Failures     []*ChunkError     // 失败的批次，按批次序号排序
+}
+
+// 失败批次的所有目标，可以用于重新发送
+func (r *PushListAllResult) FailedTargets() []string {
+	var targets []string
+	for _, f := range r.Failures {
+		targets = append(targets, f.Targets...)
+	}
+	return targets
+}
+
+// 将目标按size分批
+func splitChunks(targets []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(targets)+size-1)/size)
+	for start := 0; start < len(targets); start += size {
+		end := start + size
+		if end > len(targets) {
+			end = len(targets)
+		}
+		chunks = append(chunks, targets[start:end])
+	}
+	return chunks
+}
+
+// 按并发数执行n个任务，ctx结束后不再开始新的任务
+func runChunks(ctx context.Context, n, concurrency int, run func(i int) error) []error {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = run(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// 将任意数量的目标分批调用tolist接口发送，每批最多1000个
+//  taskId	save_list_body返回的任务号
+//  targets	cid列表，opts.Alias为true时为别名列表
+//  opts	分批设置，可以为nil
+//
+//  每批的请求同样经过限流、重试和配额检查，部分批次失败时返回的result中记录了失败的批次，
+//  err为第一个失败批次的 ChunkError
+func (c *Client) PushListAll(taskId string, targets []string, opts *PushListOptions) (result PushListAllResult, err error) {
+	return c.PushListAllContext(context.Background(), taskId, targets, opts)
+}
+
+// 同 PushListAll，ctx可用于取消请求或设置超时，ctx结束后未开始的批次记为失败
+func (c *Client) PushListAllContext(ctx context.Context, taskId string, targets []string, opts *PushListOptions) (result PushListAllResult, err error) {
+	var o PushListOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChunkSize <= 0 || o.ChunkSize > maxPushListTargets {
+		o.ChunkSize = maxPushListTargets
+	}
+
+	var v validator
+	v.required("taskid", taskId)
+	v.check(len(targets) > 0, "targets", "is required")
+	if err = v.err(); err != nil {
+		return
+	}
+
+	chunks := splitChunks(targets, o.ChunkSize)
+	results := make([]PushListResult, len(chunks))
+	errs := runChunks(ctx, len(chunks), o.Concurrency, func(i int) (err error) {
+		pushList := &PushList{TaskId: taskId, NeedDetail: o.NeedDetail}
+		if o.Alias {
+			pushList.Alias = chunks[i]
+		} else {
+			pushList.Cid = chunks[i]
+		}
+		results[i], err = c.PushListContext(ctx, pushList)
+		return
+	})
+
+	result = PushListAllResult{TaskId: taskId, Chunks: len(chunks)}
+	for i, chunkErr := range errs {
+		if chunkErr != nil {
+			result.Failures = append(result.Failures, &ChunkError{Index: i, Targets: chunks[i], Err: chunkErr})
+			continue
+		}
+		result.Sent += len(chunks[i])
+		result.CidDetails = mergeDetails(result.CidDetails, results[i].CidDetails)
+		result.AliasDetails = mergeDetails(result.AliasDetails, results[i].AliasDetails)
+	}
+	if len(result.Failures) > 0 {
+		err = result.Failures[0]
+	}
+	return
+}
+
+func mergeDetails(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// SinglePushBatchAll 的分批设置，nil时使用默认值
+type SinglePushBatchOptions struct {
+	ChunkSize   int // 每批的消息数，默认且最多200
+	Concurrency int // 同时发送的批数，默认4
+}
+
+// 批量单推中一条消息的结果
+type SinglePushBatchItem struct {
+	Push      *Push  // 对应的推送消息
+	RequestId string // 发送时使用的请求唯一标识，Push没有设置时为生成的标识
+	TaskId    string // 任务编号
+	Status    string // 推送状态，如 successed_online
+	Err       error  // 本条消息失败的原因，包括消息检查错误、所在批次的请求错误和失败的推送状态
+}
+
+// 将任意数量的消息分批调用批量单推接口发送，每批最多200条
+//  pushList	要推送的消息，没有RequestId时自动生成，生成的标识记录在item.RequestId中，不修改pushList
+//  opts	分批设置，可以为nil
+//
+//  返回的items与pushList一一对应，按RequestId与服务端返回的结果关联，
+//  检查不通过的消息不发送，err为第一条失败消息的错误
+func (c *Client) SinglePushBatchAll(pushList []*Push, opts *SinglePushBatchOptions) (items []SinglePushBatchItem, err error) {
+	return c.SinglePushBatchAllContext(context.Background(), pushList, opts)
+}
+
+// 同 SinglePushBatchAll，ctx可用于取消请求或设置超时
+func (c *Client) SinglePushBatchAllContext(ctx context.Context, pushList []*Push, opts *SinglePushBatchOptions) (items []SinglePushBatchItem, err error) {
+	var o SinglePushBatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChunkSize <= 0 || o.ChunkSize > maxSinglePushBatch {
+		o.ChunkSize = maxSinglePushBatch
+	}
+
+	// 先检查消息并生成RequestId，只发送检查通过的消息
+	items = make([]SinglePushBatchItem, len(pushList))
+	requestIds := make(map[string]bool, len(pushList))
+	var valid []int
+	for i, push := range pushList {
+		item := &items[i]
+		item.Push = push
+		if item.Err = c.validateSingle(push); item.Err != nil {
+			continue
+		}
+		if item.RequestId = push.RequestId; item.RequestId == "" {
+			for item.RequestId = newRequestId(); requestIds[item.RequestId]; {
+				item.RequestId = newRequestId()
+			}
+		} else if requestIds[item.RequestId] {
+			var v validator
+			v.add("requestid", "duplicate requestid %s", item.RequestId)
+			item.Err = v.err()
+			continue
+		}
+		requestIds[item.RequestId] = true
+		valid = append(valid, i)
+	}
+
+	chunks := splitIndexes(valid, o.ChunkSize)
+	errs := runChunks(ctx, len(chunks), o.Concurrency, func(n int) error {
+		chunk := chunks[n]
+		batch := make([]*Push, len(chunk))
+		for j, i := range chunk {
+			// 发送设置了标识的副本
+			push := *pushList[i]
+			push.RequestId = items[i].RequestId
+			batch[j] = &push
+		}
+		result, err := c.SinglePushBatchContext(ctx, batch, true)
+		if err != nil {
+			return err
+		}
+		correlateBatch(items, chunk, result)
+		return nil
+	})
+
+	for n, chunkErr := range errs {
+		if chunkErr == nil {
+			continue
+		}
+		for _, i := range chunks[n] {
+			items[i].Err = chunkErr
+		}
+	}
+	for _, item := range items {
+		if item.Err != nil {
+			return items, item.Err
+		}
+	}
+	return items, nil
+}
+
+// 将下标按size分批
+func splitIndexes(indexes []int, size int) [][]int {
+	var chunks [][]int
+	for start := 0; start < len(indexes); start += size {
+		end := start + size
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		chunks = append(chunks, indexes[start:end])
+	}
+	return chunks
+}
+
+// 按RequestId将一批的结果填入对应的item，结果中没有RequestId时按顺序对应
+func correlateBatch(items []SinglePushBatchItem, chunk []int, result SinglePushBatchResult) {
+	byRequestId := make(map[string]int, len(result.Details))
+	for j, detail := range result.Details {
+		if detail.RequestId != "" {
+			byRequestId[detail.RequestId] = j
+		}
+	}
+
+	for pos, i := range chunk {
+		item := &items[i]
+		j, ok := byRequestId[item.RequestId]
+		if !ok && len(byRequestId) == 0 && pos < len(result.Details) {
+			j, ok = pos, true
+		}
+		if !ok {
+			item.Err = fmt.Errorf("getui: no result for requestid %s", item.RequestId)
+			continue
+		}
+
+		detail := result.Details[j]
+		item.TaskId, item.Status = detail.TaskId, detail.Status
+		if !isSuccess(detail.Status) {
+			item.Err = &APIError{Result: detail.Status, Endpoint: "push_single_batch", RequestId: item.RequestId}
+		}
+	}
+}
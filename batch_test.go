@@ -0,0 +1,126 @@
+package GeTuiGo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/litinghong/GeTuiGoClient/getuitest"
+)
+
+// 生成n个测试用的cid，第一个为testCid
+func newTestCids(n int) []string {
+	cids := []string{testCid}
+	for i := 1; i < n; i++ {
+		cids = append(cids, fmt.Sprintf("batch-cid-%05d", i))
+	}
+	return cids
+}
+
+func saveTestListBody(t *testing.T, client *Client) string {
+	_, taskId, _, err := client.SaveListBody(&Push{Template: TmplTransmission{TransmissionContent: "PushListAll"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return taskId
+}
+
+func TestClient_PushListAll(t *testing.T) {
+	client := getClient(t)
+	taskId := saveTestListBody(t, client)
+
+	cids := newTestCids(2500)
+	before := fakeServer.RequestCount("push_list")
+	result, err := client.PushListAll(taskId, cids, &PushListOptions{NeedDetail: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Chunks != 3 || result.Sent != len(cids) || len(result.CidDetails) != len(cids) || len(result.Failures) != 0 {
+		t.Fatalf("unexpected result: chunks %d, sent %d, details %d", result.Chunks, result.Sent, len(result.CidDetails))
+	}
+	if fakeServer.RequestCount("push_list")-before != 3 {
+		t.Fatal("expected 3 push_list requests")
+	}
+	if result.CidDetails[testCid] != ResultSuccessOnline {
+		t.Fatalf("unexpected status %s", result.CidDetails[testCid])
+	}
+}
+
+func TestClient_PushListAll_Failures(t *testing.T) {
+	client := getClient(t)
+	taskId := saveTestListBody(t, client)
+
+	fakeServer.Fail("push_list", getuitest.Failure{Result: ResultOtherError, Times: 1})
+	defer fakeServer.ClearFailures()
+
+	cids := newTestCids(25)
+	result, err := client.PushListAll(taskId, cids, &PushListOptions{ChunkSize: 10, Concurrency: 1, NeedDetail: true})
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Index != 0 || !errors.Is(err, ErrOtherError) {
+		t.Fatalf("expected the first chunk to fail, got %v", err)
+	}
+	if result.Chunks != 3 || result.Sent != 15 || len(result.CidDetails) != 15 {
+		t.Fatalf("unexpected result: sent %d, details %d", result.Sent, len(result.CidDetails))
+	}
+	if failed := result.FailedTargets(); len(failed) != 10 || failed[0] != testCid {
+		t.Fatalf("unexpected failed targets %v", failed)
+	}
+
+	// ctx结束后未发送的批次记为失败
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = client.PushListAllContext(ctx, taskId, cids, &PushListOptions{ChunkSize: 10})
+	if !errors.Is(err, context.Canceled) || len(result.Failures) != 3 || result.Sent != 0 {
+		t.Fatalf("expected all chunks to be canceled, got %v", err)
+	}
+}
+
+func TestClient_SinglePushBatchAll(t *testing.T) {
+	client := getClient(t)
+
+	cids := newTestCids(450)
+	pushList := make([]*Push, 0, len(cids)+2)
+	for _, cid := range cids {
+		pushList = append(pushList, &Push{Template: TmplTransmission{TransmissionContent: "batch " + cid}, Cid: cid})
+	}
+	// 检查不通过和RequestId重复的消息不发送
+	pushList = append(pushList, &Push{Cid: testCid})
+	pushList = append(pushList, &Push{Template: TmplTransmission{TransmissionContent: "dup"}, Cid: testCid, RequestId: "dup"})
+	pushList[1].RequestId = "dup"
+
+	before := fakeServer.RequestCount("push_single_batch")
+	items, err := client.SinglePushBatchAll(pushList, nil)
+	if !errors.Is(err, ErrNoUser) {
+		t.Fatalf("expected ErrNoUser for unknown cids, got %v", err)
+	}
+	if fakeServer.RequestCount("push_single_batch")-before != 3 {
+		t.Fatal("expected 3 push_single_batch requests")
+	}
+	if len(items) != len(pushList) {
+		t.Fatalf("expected %d items, got %d", len(pushList), len(items))
+	}
+
+	for i, item := range items[:len(cids)] {
+		if item.Push != pushList[i] || item.RequestId == "" {
+			t.Fatalf("item %d is not matched to its push", i)
+		}
+	}
+	if pushList[0].RequestId != "" || items[1].RequestId != "dup" {
+		t.Fatal("generated requestid should not be written to the push")
+	}
+	if items[0].Err != nil || items[0].Status != ResultSuccessOnline || items[0].TaskId == "" {
+		t.Fatalf("unexpected first item %+v", items[0])
+	}
+	if items[1].Status != ResultNoUser || !errors.Is(items[1].Err, ErrNoUser) {
+		t.Fatalf("unexpected second item %+v", items[1])
+	}
+	if !errors.Is(items[len(cids)].Err, ErrInvalidParam) || !errors.Is(items[len(cids)+1].Err, ErrInvalidParam) {
+		t.Fatal("expected validation errors for the last two items")
+	}
+
+	// 单次批量单推超过200条时返回错误
+	if _, err = client.SinglePushBatch(pushList[:maxSinglePushBatch+1], false); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected invalid param error, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"time"
+)
+
+// 离线消息最长保存时间
+const maxOfflineExpire = 72 * time.Hour
+
+// 推送消息构造器，消息应用类型由模板决定，Build时检查消息是否完整
+//  示例：NewPush().ToCid(cid).Notification(tmpl).Offline(24 * time.Hour).Build()
+type PushBuilder struct {
+	push    Push
+	message Message
+	err     error // 第一个设置错误，Build时返回
+}
+
+// 创建推送消息构造器
+func NewPush() *PushBuilder {
+	return &PushBuilder{}
+}
+
+// 记录第一个设置错误
+func (b *PushBuilder) fail(err error) *PushBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// 推送给指定cid，与ToAlias二选一
+func (b *PushBuilder) ToCid(cid string) *PushBuilder {
+	b.push.Cid = cid
+	return b
+}
+
+// 推送给指定别名，与ToCid二选一
+func (b *PushBuilder) ToAlias(alias string) *PushBuilder {
+	b.push.Alias = alias
+	return b
+}
+
+// 设置消息模板，只能设置一次
+func (b *PushBuilder) Template(tmpl INotify) *PushBuilder {
+	if tmpl == nil {
+		return b.fail(errors.New("getui: push template is nil"))
+	}
+	if b.push.Template != nil {
+		return b.fail(errors.New("getui: push template is already set to " + b.push.Template.GetMsgType()))
+	}
+	b.push.Template = tmpl
+	return b
+}
+
+// 点开通知打开应用
+func (b *PushBuilder) Notification(tmpl TmplNotification) *PushBuilder {
+	return b.Template(tmpl)
+}
+
+// 点开通知打开网页
+func (b *PushBuilder) Link(tmpl TmplLink) *PushBuilder {
+	return b.Template(tmpl)
+}
+
+// 点击通知弹窗下载
+func (b *PushBuilder) NotifyPopLoad(tmpl TmplNotifyPopLoad) *PushBuilder {
+	return b.Template(tmpl)
+}
+
+// 点开通知打开应用内特定页面
+func (b *PushBuilder) StartActivity(tmpl TmplStartActivity) *PushBuilder {
+	return b.Template(tmpl)
+}
+
+// 透传消息
+func (b *PushBuilder) Transmission(tmpl TmplTransmission) *PushBuilder {
+	return b.Template(tmpl)
+}
+
+// 用户离线时保存消息，上线后下发
+//  expire	离线保存时间，最长72小时
+func (b *PushBuilder) Offline(expire time.Duration) *PushBuilder {
+	if expire <= 0 || expire > maxOfflineExpire {
+		return b.fail(errors.New("getui: offline expire must be between 1ms and 72h, got " + expire.String()))
+	}
+	b.message.IsOffline = true
+	b.message.OfflineExpireTime = int(expire / time.Millisecond)
+	return b
+}
+
+// 只在wifi网络下推送
+func (b *PushBuilder) WifiOnly() *PushBuilder {
+	b.message.PushNetworkType = 1
+	return b
+}
+
+// 设置请求唯一标识，为空时发送前自动生成
+func (b *PushBuilder) RequestId(requestId string) *PushBuilder {
+	b.push.RequestId = requestId
+	return b
+}
+
+// 设置apns推送消息，iOS用户离线时使用，可以使用 NewApns 构造
+func (b *PushBuilder) ApnPushInfo(info *ApnPushInfo) *PushBuilder {
+	b.push.PushInfo = info
+	return b
+}
+
+// 添加筛选条件，用于 PushToApp，多个条件需要同时满足
+func (b *PushBuilder) Condition(conds ...Condition) *PushBuilder {
+	b.push.AppendCondition(conds...)
+	return b
+}
+
+// 推送速度控制，用于 PushToApp
+func (b *PushBuilder) Speed(speed int) *PushBuilder {
+	b.push.SetSpeed(speed)
+	return b
+}
+
+// 设置任务组名，之后可以用 GetGroupReport 查询整组的推送结果
+func (b *PushBuilder) TaskName(name string) *PushBuilder {
+	b.push.SetTaskName(name)
+	return b
+}
+
+// 定时下发时间，用于 PushToApp
+func (b *PushBuilder) PushTime(pushTime time.Time) *PushBuilder {
+	b.push.SetPushTime(pushTime)
+	return b
+}
+
+// 设定展示时间段
+func (b *PushBuilder) Duration(begin, end time.Time) *PushBuilder {
+	if !end.After(begin) {
+		return b.fail(errors.New("getui: duration end must be after begin"))
+	}
+	b.push.SetDuration(begin, end)
+	return b
+}
+
+// 生成推送消息，设置有误或消息不完整时返回错误，消息的检查同 Push.Validate
+//  每次调用返回新的Push，可以在修改目标后重复调用
+func (b *PushBuilder) Build() (*Push, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	push := b.push
+	message := b.message
+	push.Message = &message
+	push.conditions = append([]Condition(nil), b.push.conditions...)
+	if push.Template != nil {
+		message.MsgType = push.Template.GetMsgType()
+	}
+
+	if err := push.Validate(); err != nil {
+		return nil, err
+	}
+	return &push, nil
+}
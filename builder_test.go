@@ -0,0 +1,63 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPushBuilder_Build(t *testing.T) {
+	push, err := NewPush().
+		ToCid(testCid).
+		Notification(TmplNotification{Style: newTestStyle("Build")}).
+		Offline(24 * time.Hour).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data map[string]json.RawMessage
+	if err = json.Unmarshal([]byte(push.ToJsonString("appKey")), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	var message Message
+	if err = json.Unmarshal(data["message"], &message); err != nil {
+		t.Fatal(err)
+	}
+	if message.MsgType != TypeNotification || !message.IsOffline || message.OfflineExpireTime != 86400000 {
+		t.Fatalf("unexpected message %+v", message)
+	}
+	if string(data["notification"]) == "null" || data["notification"] == nil {
+		t.Fatal("notification template is missing")
+	}
+
+	client := getClient(t)
+	result, err := client.SinglePush(push)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ResultSuccessOnline {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestPushBuilder_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *PushBuilder
+	}{
+		{"no template", NewPush().ToCid(testCid)},
+		{"two templates", NewPush().Notification(TmplNotification{}).Transmission(TmplTransmission{})},
+		{"nil template", NewPush().Template(nil)},
+		{"cid and alias", NewPush().ToCid(testCid).ToAlias("alias").Transmission(TmplTransmission{})},
+		{"offline too long", NewPush().Transmission(TmplTransmission{}).Offline(73 * time.Hour)},
+		{"reversed duration", NewPush().Transmission(TmplTransmission{}).Duration(time.Now(), time.Now().Add(-time.Hour))},
+	}
+
+	for _, tt := range tests {
+		if push, err := tt.builder.Build(); err == nil {
+			t.Errorf("%s: expected error, got %+v", tt.name, push)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package GeTuiGo
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 回调请求体的最大长度
+const maxCallbackBody = 1 << 20
+
+// 回调校验失败，返回401
+var (
+	errCallbackAppId = errors.New("getui: callback appid does not match")
+	errCallbackSign  = errors.New("getui: invalid callback sign")
+)
+
+// 个推推送到业务方地址的消息回执、点击回调事件
+type CallbackEvent struct {
+	AppId    string    // 应用id
+	Cid      string    // 用户cid
+	Alias    string    // 用户别名，没有时为空
+	TaskId   string    // 任务号
+	MsgId    string    // 消息id
+	ActionId string    // 回调的动作码，区分到达、展示、点击等，具体取值见个推后台的回调配置
+	Code     string    // 结果码，0为成功
+	Desc     string    // 结果描述
+	Time     time.Time // 事件发生时间
+}
+
+// 回调事件监听函数，在处理请求的goroutine中同步调用，不能阻塞
+type CallbackListener func(event CallbackEvent)
+
+// 接收个推回调的http.Handler，校验签名后将事件分发给监听函数
+//  签名为 md5(appid + cid + taskid + msgid + masterSecret)，校验不通过时返回401且不分发任何事件
+//  请求体可以是单个事件或事件数组，格式错误时返回400
+type CallbackHandler struct {
+	appId        string
+	masterSecret string
+
+	mu        sync.RWMutex
+	listeners []callbackListener
+}
+
+type callbackListener struct {
+	actionId string // 为空时接收所有事件
+	fn       CallbackListener
+}
+
+// 创建回调处理器
+func NewCallbackHandler(appId, masterSecret string) *CallbackHandler {
+	return &CallbackHandler{appId: appId, masterSecret: masterSecret}
+}
+
+// 使用客户端的appId和masterSecret创建回调处理器
+func (c *Client) NewCallbackHandler() *CallbackHandler {
+	return NewCallbackHandler(c.appId, c.masterSecret)
+}
+
+// 监听所有回调事件
+func (h *CallbackHandler) Listen(fn CallbackListener) {
+	h.ListenAction("", fn)
+}
+
+// 监听指定动作码的回调事件
+func (h *CallbackHandler) ListenAction(actionId string, fn CallbackListener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, callbackListener{actionId: actionId, fn: fn})
+}
+
+// 回调请求中的一个事件，数字字段兼容字符串和数字两种格式
+type callbackPayload struct {
+	AppId    string     `json:"appid"`
+	Cid      string     `json:"cid"`
+	Alias    string     `json:"alias"`
+	TaskId   string     `json:"taskid"`
+	MsgId    string     `json:"msgid"`
+	ActionId flexString `json:"actionId"`
+	Code     flexString `json:"code"`
+	Desc     string     `json:"desc"`
+	Sign     string     `json:"sign"`
+	RecvTime flexString `json:"recvtime"` // 毫秒时间戳
+}
+
+// 字符串或数字
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, (*string)(s))
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*s = flexString(n)
+	return nil
+}
+
+// 计算回调签名
+func (h *CallbackHandler) sign(p *callbackPayload) string {
+	sum := md5.Sum([]byte(p.AppId + p.Cid + p.TaskId + p.MsgId + h.masterSecret))
+	return hex.EncodeToString(sum[:])
+}
+
+// 校验签名并转换为事件
+func (h *CallbackHandler) event(p *callbackPayload) (event CallbackEvent, err error) {
+	if p.AppId != h.appId {
+		return event, errCallbackAppId
+	}
+	if subtle.ConstantTimeCompare([]byte(h.sign(p)), []byte(p.Sign)) != 1 {
+		return event, errCallbackSign
+	}
+
+	event = CallbackEvent{
+		AppId:    p.AppId,
+		Cid:      p.Cid,
+		Alias:    p.Alias,
+		TaskId:   p.TaskId,
+		MsgId:    p.MsgId,
+		ActionId: string(p.ActionId),
+		Code:     string(p.Code),
+		Desc:     p.Desc,
+	}
+	if p.RecvTime != "" {
+		ms, err := strconv.ParseInt(string(p.RecvTime), 10, 64)
+		if err != nil {
+			return event, errors.New("getui: invalid callback recvtime")
+		}
+		event.Time = time.Unix(0, ms*int64(time.Millisecond)).In(Location)
+	}
+	return
+}
+
+// 解析回调请求体
+func (h *CallbackHandler) parse(body []byte) ([]CallbackEvent, error) {
+	var payloads []*callbackPayload
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &payloads); err != nil {
+			return nil, err
+		}
+	} else {
+		p := &callbackPayload{}
+		if err := json.Unmarshal(body, p); err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, p)
+	}
+
+	events := make([]CallbackEvent, len(payloads))
+	for i, p := range payloads {
+		if p == nil {
+			return nil, errors.New("getui: empty callback event")
+		}
+		event, err := h.event(p)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+	return events, nil
+}
+
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBody))
+	if err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	events, err := h.parse(body)
+	switch {
+	case errors.Is(err, errCallbackAppId) || errors.Is(err, errCallbackSign):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	listeners := h.listeners
+	h.mu.RUnlock()
+	for _, event := range events {
+		for _, l := range listeners {
+			if l.actionId == "" || l.actionId == event.ActionId {
+				l.fn(event)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"result":"ok"}`))
+}
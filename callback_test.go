@@ -0,0 +1,107 @@
+package GeTuiGo
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func callbackSign(appId, cid, taskId, msgId, masterSecret string) string {
+	sum := md5.Sum([]byte(appId + cid + taskId + msgId + masterSecret))
+	return hex.EncodeToString(sum[:])
+}
+
+func postCallback(h http.Handler, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/getui/callback", strings.NewReader(body)))
+	return w
+}
+
+func TestCallbackHandler(t *testing.T) {
+	h := NewCallbackHandler("appId", "secret")
+	var all, clicks []CallbackEvent
+	h.Listen(func(e CallbackEvent) { all = append(all, e) })
+	h.ListenAction("10010", func(e CallbackEvent) { clicks = append(clicks, e) })
+
+	sign := callbackSign("appId", "cid1", "task1", "msg1", "secret")
+	body := fmt.Sprintf(`{"appid":"appId","cid":"cid1","taskid":"task1","msgid":"msg1","code":"0","desc":"ok","actionId":10009,"recvtime":1600000000123,"sign":"%s"}`, sign)
+	if w := postCallback(h, body); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d %s", w.Code, w.Body)
+	}
+	if len(all) != 1 || len(clicks) != 0 {
+		t.Fatalf("unexpected dispatch %+v %+v", all, clicks)
+	}
+	e := all[0]
+	if e.Cid != "cid1" || e.TaskId != "task1" || e.MsgId != "msg1" || e.ActionId != "10009" || e.Code != "0" {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if e.Time.UnixNano() != 1600000000123*1e6 || e.Time.Location() != Location {
+		t.Fatalf("unexpected time %v", e.Time)
+	}
+
+	// 事件数组
+	sign2 := callbackSign("appId", "cid2", "task1", "msg2", "secret")
+	body = fmt.Sprintf(`[{"appid":"appId","cid":"cid1","taskid":"task1","msgid":"msg1","actionId":"10010","sign":"%s"},`+
+		`{"appid":"appId","cid":"cid2","taskid":"task1","msgid":"msg2","actionId":"10010","sign":"%s"}]`, sign, sign2)
+	if w := postCallback(h, body); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d %s", w.Code, w.Body)
+	}
+	if len(all) != 3 || len(clicks) != 2 || clicks[1].Cid != "cid2" || !clicks[1].Time.IsZero() {
+		t.Fatalf("unexpected dispatch %+v %+v", all, clicks)
+	}
+}
+
+func TestCallbackHandler_Reject(t *testing.T) {
+	h := NewCallbackHandler("appId", "secret")
+	dispatched := 0
+	h.Listen(func(CallbackEvent) { dispatched++ })
+
+	valid := fmt.Sprintf(`{"appid":"appId","cid":"cid1","taskid":"task1","msgid":"msg1","sign":"%s"}`,
+		callbackSign("appId", "cid1", "task1", "msg1", "secret"))
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"bad sign", `{"appid":"appId","cid":"cid1","taskid":"task1","msgid":"msg1","sign":"bad"}`, http.StatusUnauthorized},
+		{"other app", fmt.Sprintf(`{"appid":"other","cid":"cid1","taskid":"task1","msgid":"msg1","sign":"%s"}`,
+			callbackSign("other", "cid1", "task1", "msg1", "secret")), http.StatusUnauthorized},
+		{"one bad in batch", fmt.Sprintf(`[%s,{"appid":"appId","cid":"cid2","sign":"bad"}]`, valid), http.StatusUnauthorized},
+		{"bad json", `{"appid":`, http.StatusBadRequest},
+		{"bad type", `{"appid":1}`, http.StatusBadRequest},
+		{"null event", `[null]`, http.StatusBadRequest},
+		{"null in batch", fmt.Sprintf(`[%s,null]`, valid), http.StatusBadRequest},
+		{"bad recvtime", fmt.Sprintf(`{"appid":"appId","cid":"cid1","taskid":"task1","msgid":"msg1","recvtime":"soon","sign":"%s"}`,
+			callbackSign("appId", "cid1", "task1", "msg1", "secret")), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := postCallback(h, tt.body)
+		if w.Code != tt.code {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.code)
+		}
+		if strings.Contains(w.Body.String(), "getui") {
+			t.Errorf("%s: response exposes error %q", tt.name, w.Body)
+		}
+	}
+	if dispatched != 0 {
+		t.Fatalf("rejected requests dispatched %d events", dispatched)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/getui/callback", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+}
+
+func TestClient_NewCallbackHandler(t *testing.T) {
+	client := getClient(t)
+	h := client.NewCallbackHandler()
+	if h.appId != client.appId || h.masterSecret != client.masterSecret {
+		t.Fatal("handler should use client credentials")
+	}
+}
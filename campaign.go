@@ -0,0 +1,418 @@
+package GeTuiGo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 群推目标迭代器，恢复任务时需要按相同的顺序返回目标
+type TargetIterator interface {
+	// 返回下一个目标，没有更多目标时返回 io.EOF
+	Next() (string, error)
+}
+
+type sliceTargets struct {
+	targets []string
+	pos     int
+}
+
+func (it *sliceTargets) Next() (string, error) {
+	if it.pos >= len(it.targets) {
+		return "", io.EOF
+	}
+	it.pos++
+	return it.targets[it.pos-1], nil
+}
+
+// 按切片顺序返回目标的迭代器
+func SliceTargets(targets []string) TargetIterator {
+	return &sliceTargets{targets: targets}
+}
+
+// 群推任务的进度，由 CheckpointStore 保存
+type CampaignCheckpoint struct {
+	Id        string      `json:"id"`
+	TaskId    string      `json:"taskid"`              // save_list_body返回的任务号
+	ChunkSize int         `json:"chunk_size"`          // 每批的目标数，恢复时沿用
+	Offset    int         `json:"offset"`              // 之前的目标都已发送
+	Completed map[int]int `json:"completed,omitempty"` // Offset之后已发送的批次，起始位置 -> 目标数
+	Sent      int         `json:"sent"`                // 已发送的目标数
+	Done      bool        `json:"done"`                // 所有目标都已发送
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (cp *CampaignCheckpoint) clone() *CampaignCheckpoint {
+	c := *cp
+	c.Completed = make(map[int]int, len(cp.Completed))
+	for k, v := range cp.Completed {
+		c.Completed[k] = v
+	}
+	return &c
+}
+
+// 群推任务进度的存储，实现需要并发安全
+type CheckpointStore interface {
+	// 读取进度，没有记录时返回nil
+	Load(id string) (*CampaignCheckpoint, error)
+	// 保存进度
+	Save(cp *CampaignCheckpoint) error
+}
+
+// 内存中的进度存储，进程退出后丢失，可用于测试
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]*CampaignCheckpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]*CampaignCheckpoint)}
+}
+
+func (s *MemoryCheckpointStore) Load(id string) (*CampaignCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cp := s.checkpoints[id]; cp != nil {
+		return cp.clone(), nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryCheckpointStore) Save(cp *CampaignCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[cp.Id] = cp.clone()
+	return nil
+}
+
+// 文件进度存储，每个任务保存为目录下的 <id>.json
+type FileCheckpointStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// 创建文件进度存储
+//  dir	保存进度文件的目录，不存在时自动创建
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+func (s *FileCheckpointStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", fmt.Errorf("getui: invalid campaign id %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func (s *FileCheckpointStore) Load(id string) (*CampaignCheckpoint, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &CampaignCheckpoint{}
+	if err = json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("getui: checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// 先写入临时文件再重命名，保存过程中崩溃不会损坏已有的进度
+func (s *FileCheckpointStore) Save(cp *CampaignCheckpoint) error {
+	path, err := s.path(cp.Id)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := ioutil.TempFile(s.dir, cp.Id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// 群推任务：保存一次消息体，再将迭代器中的目标分批调用tolist发送，进度保存到Store
+//  使用相同的Id重新运行时从保存的进度继续，已发送的批次不再发送；
+//  某批失败时只停止发送新的批次，正在发送的批次完成后照常保存进度。
+//  以下情况该批在恢复后会重新发送：一批发送成功后、保存进度前进程退出；
+//  调用方的ctx在请求已到达服务端后结束
+type Campaign struct {
+	Id          string                 // 任务标识，用于保存和恢复进度，设置了Store时必传
+	Push        *Push                  // 要推送的消息，恢复时不再使用
+	Targets     TargetIterator         // 目标，恢复时需要按相同的顺序返回
+	Alias       bool                   // 目标为别名，默认为cid
+	Store       CheckpointStore        // 进度存储，为nil时不保存进度
+	ChunkSize   int                    // 每批的目标数，默认且最多1000，恢复时沿用保存的值
+	Concurrency int                    // 同时发送的批数，默认4
+	OnProgress  func(CampaignProgress) // 每批发送完成后调用，不能阻塞
+}
+
+// 群推任务的进度
+type CampaignProgress struct {
+	TaskId  string // save_list_body返回的任务号
+	Sent    int    // 已发送的目标数，包括之前运行时发送的
+	Skipped int    // 本次运行跳过的目标数，即之前运行时已发送的
+	Failed  int    // 本次运行发送失败的目标数
+	Done    bool   // 所有目标都已发送
+}
+
+// 运行群推任务，返回最终的进度
+//  某批发送失败时不再发送新的批次，等待正在发送的批次完成后停止，返回该批的 ChunkError，重新运行即可从失败处继续
+func (c *Client) RunCampaign(campaign *Campaign) (CampaignProgress, error) {
+	return c.RunCampaignContext(context.Background(), campaign)
+}
+
+// 同 RunCampaign，ctx结束时停止发送新的批次，正在发送的请求随ctx取消
+func (c *Client) RunCampaignContext(ctx context.Context, campaign *Campaign) (progress CampaignProgress, err error) {
+	run := &campaignRun{client: c, campaign: campaign}
+	if err = run.start(ctx); err != nil {
+		return run.progress, err
+	}
+	if run.cp.Done {
+		run.progress.Done = true
+		return run.progress, nil
+	}
+	return run.progress, run.send(ctx)
+}
+
+// 一次群推任务的运行状态
+type campaignRun struct {
+	client   *Client
+	campaign *Campaign
+
+	mu       sync.Mutex
+	cp       *CampaignCheckpoint
+	progress CampaignProgress
+	err      error         // 第一个发送或保存错误
+	stop     chan struct{} // 出错后关闭，停止发送新的批次
+	stopped  bool
+}
+
+// 停止发送新的批次，调用时需要持有r.mu
+func (r *campaignRun) halt() {
+	if !r.stopped {
+		r.stopped = true
+		close(r.stop)
+	}
+}
+
+func (r *campaignRun) isStopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
+
+// 读取或创建进度，新任务先保存消息体
+func (r *campaignRun) start(ctx context.Context) (err error) {
+	campaign := r.campaign
+	if campaign.Targets == nil {
+		return errors.New("getui: campaign targets are required")
+	}
+	if campaign.Store != nil {
+		if campaign.Id == "" {
+			return errors.New("getui: campaign id is required when store is set")
+		}
+		if r.cp, err = campaign.Store.Load(campaign.Id); err != nil {
+			return
+		}
+	}
+
+	if r.cp == nil {
+		if campaign.Push == nil {
+			return errors.New("getui: campaign push is required")
+		}
+		chunkSize := campaign.ChunkSize
+		if chunkSize <= 0 || chunkSize > maxPushListTargets {
+			chunkSize = maxPushListTargets
+		}
+
+		var taskId string
+		if _, taskId, _, err = r.client.SaveListBodyContext(ctx, campaign.Push); err != nil {
+			return
+		}
+		r.cp = &CampaignCheckpoint{Id: campaign.Id, TaskId: taskId, ChunkSize: chunkSize}
+		if err = r.save(); err != nil {
+			return
+		}
+	}
+	if r.cp.Completed == nil {
+		r.cp.Completed = make(map[int]int)
+	}
+	r.progress = CampaignProgress{TaskId: r.cp.TaskId, Sent: r.cp.Sent}
+	return
+}
+
+func (r *campaignRun) save() error {
+	r.cp.UpdatedAt = time.Now()
+	if r.campaign.Store == nil {
+		return nil
+	}
+	return r.campaign.Store.Save(r.cp)
+}
+
+// 读取下一批目标
+func (r *campaignRun) next(size int) (targets []string, err error) {
+	for len(targets) < size {
+		target, err := r.campaign.Targets.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getui: campaign targets: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	return
+}
+
+// 跳过已发送的目标，分批并发发送剩余的目标
+//  某批失败时不取消正在发送的请求：请求可能已到达服务端，需要等待结果并保存进度，避免恢复后重复发送
+func (r *campaignRun) send(ctx context.Context) error {
+	r.stop = make(chan struct{})
+
+	if _, err := r.next(r.cp.Offset); err != nil {
+		return err
+	}
+	r.progress.Skipped = r.cp.Offset
+
+	concurrency := r.campaign.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	var readErr error
+	for start := r.cp.Offset; ctx.Err() == nil && !r.isStopped(); {
+		var targets []string
+		if targets, readErr = r.next(r.cp.ChunkSize); readErr != nil || len(targets) == 0 {
+			break
+		}
+		chunkStart := start
+		start += len(targets)
+
+		r.mu.Lock()
+		_, sent := r.cp.Completed[chunkStart]
+		if sent {
+			r.progress.Skipped += len(targets)
+		}
+		r.mu.Unlock()
+		if sent {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		case <-r.stop:
+		}
+		if ctx.Err() != nil || r.isStopped() {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r.sendChunk(ctx, chunkStart, targets)
+		}()
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.err
+	if err == nil {
+		err = readErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		r.cp.Done = true
+		r.progress.Done = true
+		err = r.save()
+	}
+	return err
+}
+
+// 发送一批目标，成功后更新进度
+func (r *campaignRun) sendChunk(ctx context.Context, start int, targets []string) {
+	pushList := &PushList{TaskId: r.cp.TaskId}
+	if r.campaign.Alias {
+		pushList.Alias = targets
+	} else {
+		pushList.Cid = targets
+	}
+	_, err := r.client.PushListContext(ctx, pushList)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.progress.Failed += len(targets)
+		if r.err == nil && ctx.Err() == nil {
+			r.err = &ChunkError{Index: start / r.cp.ChunkSize, Targets: targets, Err: err}
+		}
+		r.halt()
+		return
+	}
+
+	// 连续完成的批次合并到Offset
+	r.cp.Completed[start] = len(targets)
+	for n, ok := r.cp.Completed[r.cp.Offset]; ok; n, ok = r.cp.Completed[r.cp.Offset] {
+		delete(r.cp.Completed, r.cp.Offset)
+		r.cp.Offset += n
+	}
+	r.cp.Sent += len(targets)
+	r.progress.Sent = r.cp.Sent
+
+	if err = r.save(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("getui: save campaign checkpoint: %w", err)
+		r.halt()
+	}
+	if r.campaign.OnProgress != nil {
+		r.campaign.OnProgress(r.progress)
+	}
+}
@@ -0,0 +1,233 @@
+package GeTuiGo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/litinghong/GeTuiGoClient/getuitest"
+)
+
+func TestClient_RunCampaign(t *testing.T) {
+	client := getClient(t)
+
+	before := fakeServer.RequestCount("push_list")
+	progress, err := client.RunCampaign(&Campaign{
+		Push:    &Push{Template: TmplTransmission{TransmissionContent: "campaign"}},
+		Targets: SliceTargets(newTestCids(2500)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !progress.Done || progress.Sent != 2500 || progress.TaskId == "" {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	if fakeServer.RequestCount("push_list")-before != 3 {
+		t.Fatal("expected 3 push_list requests")
+	}
+}
+
+func TestClient_RunCampaign_Resume(t *testing.T) {
+	client := getClient(t)
+
+	dir, err := ioutil.TempDir("", "getui-campaign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFileCheckpointStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testCid放在第二批
+	cids := newTestCids(2500)
+	cids[0], cids[1500] = cids[1500], cids[0]
+	newCampaign := func() *Campaign {
+		return &Campaign{
+			Id:          "resume",
+			Push:        &Push{Template: TmplTransmission{TransmissionContent: "campaign resume"}},
+			Targets:     SliceTargets(cids),
+			Store:       store,
+			Concurrency: 1,
+		}
+	}
+	defer fakeServer.ClearFailures()
+
+	// 第一批完成后模拟第二批失败
+	campaign := newCampaign()
+	campaign.OnProgress = func(p CampaignProgress) {
+		if p.Sent == 1000 {
+			fakeServer.Fail("push_list", getuitest.Failure{Result: ResultOtherError, Times: 1})
+		}
+	}
+	progress, err := client.RunCampaign(campaign)
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Index != 1 || !errors.Is(err, ErrOtherError) {
+		t.Fatalf("expected the second chunk to fail, got %v", err)
+	}
+	if progress.Done || progress.Sent != 1000 || progress.Failed != 1000 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+
+	cp, err := store.Load("resume")
+	if err != nil || cp == nil || cp.Offset != 1000 || cp.Done {
+		t.Fatalf("unexpected checkpoint %+v, %v", cp, err)
+	}
+
+	// 重新运行时跳过第一批，不再保存消息体
+	saves := fakeServer.RequestCount("save_list_body")
+	pushes := fakeServer.RequestCount("push_list")
+	progress, err = client.RunCampaign(newCampaign())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !progress.Done || progress.Sent != 2500 || progress.Skipped != 1000 || progress.TaskId != cp.TaskId {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	if fakeServer.RequestCount("save_list_body") != saves || fakeServer.RequestCount("push_list")-pushes != 2 {
+		t.Fatal("resumed campaign should only send the remaining chunks")
+	}
+
+	delivered := 0
+	for _, m := range fakeServer.MessagesTo(testCid) {
+		if m.TaskId == cp.TaskId {
+			delivered++
+		}
+	}
+	if delivered != 1 {
+		t.Fatalf("expected 1 message for the campaign, got %d", delivered)
+	}
+
+	// 已完成的任务不再发送
+	pushes = fakeServer.RequestCount("push_list")
+	if progress, err = client.RunCampaign(newCampaign()); err != nil || !progress.Done {
+		t.Fatalf("unexpected progress %+v, %v", progress, err)
+	}
+	if fakeServer.RequestCount("push_list") != pushes {
+		t.Fatal("finished campaign should not send again")
+	}
+}
+
+// 第一批返回网络错误时，第二批的请求已到达服务端、响应还没有返回
+type inFlightTransport struct {
+	armed       int32
+	first       string // 第一批中的cid
+	second      string // 第二批中的cid
+	secondSent  chan struct{}
+	firstFailed chan struct{}
+}
+
+func (tr *inFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(&tr.armed) == 0 || !strings.HasSuffix(req.URL.Path, "/push_list") {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	switch {
+	case bytes.Contains(body, []byte(`"`+tr.first+`"`)):
+		<-tr.secondSent
+		defer close(tr.firstFailed)
+		return nil, errors.New("connection reset")
+	case bytes.Contains(body, []byte(`"`+tr.second+`"`)):
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		close(tr.secondSent)
+		<-tr.firstFailed
+		select {
+		case <-req.Context().Done():
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, req.Context().Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+		return resp, err
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_RunCampaign_InFlight(t *testing.T) {
+	cids := make([]string, 6)
+	for i := range cids {
+		cids[i] = fmt.Sprintf("campaign-inflight-%d", i)
+		fakeServer.AddUser(cids[i], true)
+	}
+	tr := &inFlightTransport{armed: 1, first: cids[0], second: cids[2],
+		secondSent: make(chan struct{}), firstFailed: make(chan struct{})}
+	client, err := NewClient("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2",
+		WithBaseURL(fakeServer.BaseURL()), WithHTTPClient(&http.Client{Transport: tr}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewMemoryCheckpointStore()
+	newCampaign := func() *Campaign {
+		return &Campaign{
+			Id:          "inflight",
+			Push:        &Push{Template: TmplTransmission{TransmissionContent: "campaign in flight"}},
+			Targets:     SliceTargets(cids),
+			Store:       store,
+			ChunkSize:   2,
+			Concurrency: 2,
+		}
+	}
+
+	// 第一批失败时第二批正在发送，第二批的结果仍然保存，第三批不再发送
+	progress, err := client.RunCampaign(newCampaign())
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Index != 0 {
+		t.Fatalf("expected the first chunk to fail, got %v", err)
+	}
+	if progress.Sent != 2 || progress.Failed != 2 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+
+	atomic.StoreInt32(&tr.armed, 0)
+	if progress, err = client.RunCampaign(newCampaign()); err != nil || !progress.Done || progress.Skipped != 2 {
+		t.Fatalf("unexpected progress %+v, %v", progress, err)
+	}
+	for _, cid := range cids {
+		delivered := 0
+		for _, m := range fakeServer.MessagesTo(cid) {
+			if m.TaskId == progress.TaskId {
+				delivered++
+			}
+		}
+		if delivered != 1 {
+			t.Errorf("%s: expected 1 message, got %d", cid, delivered)
+		}
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "getui-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, _ := NewFileCheckpointStore(dir)
+
+	if cp, err := store.Load("missing"); cp != nil || err != nil {
+		t.Fatalf("expected no checkpoint, got %+v, %v", cp, err)
+	}
+	if err = store.Save(&CampaignCheckpoint{Id: "../escape"}); err == nil {
+		t.Fatal("expected error for an invalid id")
+	}
+
+	saved := &CampaignCheckpoint{Id: "c1", TaskId: "task", ChunkSize: 10, Offset: 20, Completed: map[int]int{40: 10}, Sent: 30}
+	if err = store.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := store.Load("c1")
+	if err != nil || cp.TaskId != "task" || cp.Offset != 20 || cp.Completed[40] != 10 || cp.Sent != 30 {
+		t.Fatalf("unexpected checkpoint %+v, %v", cp, err)
+	}
+}
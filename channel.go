@@ -0,0 +1,430 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 厂商通道在ups.options中的名称
+const (
+	VendorHuawei = "HW"
+	VendorXiaomi = "XM"
+	VendorOppo   = "OP"
+	VendorVivo   = "VV"
+	VendorMeizu  = "MZ"
+)
+
+// 华为通知消息重要性
+const (
+	HuaweiImportanceLow    = "LOW"    // 资讯营销类消息
+	HuaweiImportanceNormal = "NORMAL" // 服务与通讯类消息，需要同时设置Category
+)
+
+// 华为服务与通讯类消息的分类，NORMAL重要性只能使用这些分类
+var huaweiServiceCategories = map[string]bool{
+	"IM": true, "VOIP": true, "SUBSCRIPTION": true, "TRAVEL": true, "HEALTH": true, "WORK": true,
+	"ACCOUNT": true, "EXPRESS": true, "FINANCE": true, "DEVICE_REMINDER": true, "MAIL": true, "PLAY_VOICE": true,
+}
+
+// OPPO、vivo的消息分类，true为服务与通讯类，false为内容与营销类
+var oppoVivoCategories = map[string]bool{
+	"IM": true, "ACCOUNT": true, "TODO": true, "DEVICE_REMINDER": true, "ORDER": true, "SUBSCRIPTION": true,
+	"NEWS": false, "CONTENT": false, "MARKETING": false, "SOCIAL": false,
+}
+
+// 鸿蒙通知消息的分类
+var harmonyCategories = map[string]bool{
+	"IM": true, "VOIP": true, "SUBSCRIPTION": true, "TRAVEL": true, "HEALTH": true, "WORK": true,
+	"ACCOUNT": true, "EXPRESS": true, "FINANCE": true, "DEVICE_REMINDER": true, "MARKETING": true,
+}
+
+// OPPO通知栏展示级别
+const (
+	OppoNotifyLevelBar     = 1  // 通知栏
+	OppoNotifyLevelLock    = 2  // 通知栏+锁屏
+	OppoNotifyLevelHeadsUp = 16 // 通知栏+锁屏+横幅+震动+铃声，只能用于服务与通讯类消息
+)
+
+// vivo消息类型
+const (
+	VivoClassificationMarketing = 0 // 运营消息
+	VivoClassificationSystem    = 1 // 系统消息
+)
+
+// 厂商通道离线推送设置，用户离线时通过手机厂商的推送通道下发
+//  只有v2接口支持，使用 PushChannelV2.SetVendor 设置到 PushRequestV2.PushChannel
+//  Notification与Transmission二选一，只需要设置使用到的厂商，未设置的厂商使用默认参数
+type VendorChannel struct {
+	Notification *UpsNotificationV2 // 厂商通道通知内容
+	Transmission string             // 厂商通道透传内容
+	Huawei       *HuaweiOptions     // 华为、荣耀EMUI
+	Xiaomi       *XiaomiOptions     // 小米
+	Oppo         *OppoOptions       // OPPO
+	Vivo         *VivoOptions       // vivo
+	Meizu        *MeizuOptions      // 魅族
+	Harmony      *HarmonyOptions    // 鸿蒙，独立于安卓的通道，设置后才会下发
+}
+
+// 华为通道参数
+type HuaweiOptions struct {
+	Importance  string // 通知重要性，使用 HuaweiImportanceXXX 常量
+	Category    string // 消息分类，如 IM、ACCOUNT、MARKETING
+	ChannelId   string // 自定义通知渠道id
+	BadgeClass  string // 应用入口Activity类全路径，设置角标时必传
+	BadgeAddNum int    // 角标增加数，1~99
+}
+
+// 小米通道参数
+type XiaomiOptions struct {
+	ChannelId string // 通知类别id，需要先在小米开放平台申请
+}
+
+// OPPO通道参数
+type OppoOptions struct {
+	ChannelId   string // 通知渠道id
+	Category    string // 消息分类，如 IM、ACCOUNT、MARKETING
+	NotifyLevel int    // 通知栏展示级别，使用 OppoNotifyLevelXXX 常量，设置时Category必传
+}
+
+// vivo通道参数
+type VivoOptions struct {
+	Classification int    // 消息类型，使用 VivoClassificationXXX 常量
+	Category       string // 消息分类，系统消息只能使用服务与通讯类分类，运营消息只能使用内容与营销类分类
+}
+
+// 魅族通道参数
+type MeizuOptions struct {
+	NoticeMsgType int // 消息类型，0：公信消息，1：私信消息
+}
+
+// 鸿蒙通道参数
+type HarmonyOptions struct {
+	Category    string // 必传: 通知消息分类，如 IM、ACCOUNT、MARKETING
+	Want        string // 点击通知打开的页面，ClickType为intent时必传
+	BadgeAddNum int    // 角标增加数
+}
+
+// 检查厂商通道参数，不支持的组合返回错误
+func (vc *VendorChannel) Validate() error {
+	if vc == nil {
+		return nil
+	}
+	if (vc.Notification == nil) == (vc.Transmission == "") {
+		return channelError("", "exactly one of notification and transmission is required")
+	}
+	if n := vc.Notification; n != nil {
+		switch n.ClickType {
+		case ClickTypeIntent:
+			if n.Intent == "" {
+				return channelError("", "intent is required for click_type intent")
+			}
+		case ClickTypeUrl:
+			if n.Url == "" {
+				return channelError("", "url is required for click_type url")
+			}
+		case ClickTypePayload, ClickTypeStartApp, ClickTypeNone:
+		default:
+			return channelError("", "click_type %q is not supported by vendor channels", n.ClickType)
+		}
+	}
+
+	for _, check := range []func() error{vc.validateHuawei, vc.validateOppo, vc.validateVivo, vc.validateMeizu, vc.validateHarmony} {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vc *VendorChannel) validateHuawei() error {
+	o := vc.Huawei
+	if o == nil {
+		return nil
+	}
+	switch o.Importance {
+	case "", HuaweiImportanceLow:
+	case HuaweiImportanceNormal:
+		if !huaweiServiceCategories[o.Category] {
+			return channelError("huawei", "importance NORMAL requires a service category, got %q", o.Category)
+		}
+	default:
+		return channelError("huawei", "unknown importance %q", o.Importance)
+	}
+	if o.Category != "" && o.Category != "MARKETING" && !huaweiServiceCategories[o.Category] {
+		return channelError("huawei", "unknown category %q", o.Category)
+	}
+	if o.BadgeAddNum < 0 || o.BadgeAddNum > 99 {
+		return channelError("huawei", "badge_add_num must be between 1 and 99")
+	}
+	if o.BadgeAddNum > 0 && o.BadgeClass == "" {
+		return channelError("huawei", "badge_class is required when badge_add_num is set")
+	}
+	if vc.Transmission != "" && (o.Importance != "" || o.BadgeAddNum > 0 || o.ChannelId != "") {
+		return channelError("huawei", "importance, badge and channel_id are not supported for transmission")
+	}
+	return nil
+}
+
+func (vc *VendorChannel) validateOppo() error {
+	o := vc.Oppo
+	if o == nil {
+		return nil
+	}
+	service, ok := oppoVivoCategories[o.Category]
+	if o.Category != "" && !ok {
+		return channelError("oppo", "unknown category %q", o.Category)
+	}
+	switch o.NotifyLevel {
+	case 0:
+	case OppoNotifyLevelBar, OppoNotifyLevelLock:
+		if o.Category == "" {
+			return channelError("oppo", "category is required when notify_level is set")
+		}
+	case OppoNotifyLevelHeadsUp:
+		if !service {
+			return channelError("oppo", "notify_level 16 requires a service category, got %q", o.Category)
+		}
+	default:
+		return channelError("oppo", "unknown notify_level %d", o.NotifyLevel)
+	}
+	if vc.Transmission != "" && (o.NotifyLevel != 0 || o.ChannelId != "") {
+		return channelError("oppo", "notify_level and channel_id are not supported for transmission")
+	}
+	return nil
+}
+
+func (vc *VendorChannel) validateVivo() error {
+	o := vc.Vivo
+	if o == nil {
+		return nil
+	}
+	service, ok := oppoVivoCategories[o.Category]
+	if o.Category != "" && !ok {
+		return channelError("vivo", "unknown category %q", o.Category)
+	}
+	switch o.Classification {
+	case VivoClassificationMarketing:
+		if ok && service {
+			return channelError("vivo", "category %q requires classification 1", o.Category)
+		}
+	case VivoClassificationSystem:
+		if !service {
+			return channelError("vivo", "classification 1 requires a service category, got %q", o.Category)
+		}
+	default:
+		return channelError("vivo", "unknown classification %d", o.Classification)
+	}
+	return nil
+}
+
+func (vc *VendorChannel) validateMeizu() error {
+	if o := vc.Meizu; o != nil && o.NoticeMsgType != 0 && o.NoticeMsgType != 1 {
+		return channelError("meizu", "unknown notice_msg_type %d", o.NoticeMsgType)
+	}
+	return nil
+}
+
+func (vc *VendorChannel) validateHarmony() error {
+	o := vc.Harmony
+	if o == nil || vc.Notification == nil {
+		return nil
+	}
+	if !harmonyCategories[o.Category] {
+		return channelError("harmony", "unknown category %q", o.Category)
+	}
+	switch vc.Notification.ClickType {
+	case ClickTypeIntent:
+		if o.Want == "" {
+			return channelError("harmony", "want is required for click_type intent")
+		}
+	case ClickTypeStartApp:
+	default:
+		return channelError("harmony", "click_type %q is not supported", vc.Notification.ClickType)
+	}
+	return nil
+}
+
+// 厂商通道参数错误，字段路径为push_channel.厂商
+func channelError(vendor, format string, a ...interface{}) error {
+	field := "push_channel"
+	if vendor != "" {
+		field += "." + vendor
+	}
+	return ValidationErrors{{Field: field, Message: fmt.Sprintf(format, a...)}}
+}
+
+// 各厂商的扩展参数，厂商名称 -> 参数路径 -> 值
+func (vc *VendorChannel) options() map[string]map[string]interface{} {
+	all := map[string]map[string]interface{}{}
+	set := func(vendor, key string, value interface{}) {
+		if all[vendor] == nil {
+			all[vendor] = map[string]interface{}{}
+		}
+		all[vendor][key] = value
+	}
+
+	if o := vc.Huawei; o != nil {
+		if o.Importance != "" {
+			set(VendorHuawei, "/message/android/notification/importance", o.Importance)
+		}
+		if o.Category != "" {
+			set(VendorHuawei, "/message/android/category", o.Category)
+		}
+		if o.ChannelId != "" {
+			set(VendorHuawei, "/message/android/notification/channel_id", o.ChannelId)
+		}
+		if o.BadgeAddNum > 0 {
+			set(VendorHuawei, "/message/android/notification/badge/class", o.BadgeClass)
+			set(VendorHuawei, "/message/android/notification/badge/add_num", o.BadgeAddNum)
+		}
+	}
+	if o := vc.Xiaomi; o != nil && o.ChannelId != "" {
+		set(VendorXiaomi, "/extra.channel_id", o.ChannelId)
+	}
+	if o := vc.Oppo; o != nil {
+		if o.ChannelId != "" {
+			set(VendorOppo, "/channel_id", o.ChannelId)
+		}
+		if o.Category != "" {
+			set(VendorOppo, "/category", o.Category)
+		}
+		if o.NotifyLevel != 0 {
+			set(VendorOppo, "/notify_level", o.NotifyLevel)
+		}
+	}
+	if o := vc.Vivo; o != nil {
+		set(VendorVivo, "/classification", o.Classification)
+		if o.Category != "" {
+			set(VendorVivo, "/category", o.Category)
+		}
+	}
+	if o := vc.Meizu; o != nil {
+		set(VendorMeizu, "/noticeMsgType", o.NoticeMsgType)
+	}
+
+	if len(all) == 0 {
+		return nil
+	}
+	return all
+}
+
+// 转为v2接口的厂商通道消息内容，不包含iOS部分
+func (vc *VendorChannel) pushChannel() *PushChannelV2 {
+	pc := &PushChannelV2{
+		Android: &AndroidChannelV2{Ups: &UpsV2{
+			Notification: vc.Notification,
+			Transmission: vc.Transmission,
+			Options:      vc.options(),
+		}},
+	}
+
+	if o := vc.Harmony; o != nil {
+		pc.Harmony = &HarmonyChannelV2{Transmission: vc.Transmission}
+		if n := vc.Notification; n != nil {
+			pc.Harmony.Notification = &HarmonyNotificationV2{
+				Title:       n.Title,
+				Body:        n.Body,
+				Category:    o.Category,
+				ClickType:   n.ClickType,
+				Want:        o.Want,
+				NotifyId:    n.NotifyId,
+				BadgeAddNum: o.BadgeAddNum,
+			}
+			if n.ClickType == ClickTypeIntent {
+				pc.Harmony.Notification.ClickType = "want"
+			}
+		}
+	}
+	return pc
+}
+
+// 按各厂商的格式序列化，参数不合法时返回错误
+func (vc *VendorChannel) MarshalJSON() ([]byte, error) {
+	if err := vc.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(vc.pushChannel())
+}
+
+// 设置厂商通道消息内容，保留已设置的iOS部分，vc为nil时清除
+func (pc *PushChannelV2) SetVendor(vc *VendorChannel) error {
+	if vc == nil {
+		pc.Android, pc.Harmony = nil, nil
+		return nil
+	}
+	if err := vc.Validate(); err != nil {
+		return err
+	}
+	vendor := vc.pushChannel()
+	pc.Android = vendor.Android
+	pc.Harmony = vendor.Harmony
+	return nil
+}
+
+// 还原 MarshalJSON 生成的厂商通道设置
+func (vc *VendorChannel) UnmarshalJSON(data []byte) error {
+	var pc PushChannelV2
+	if err := json.Unmarshal(data, &pc); err != nil {
+		return err
+	}
+
+	channel := VendorChannel{}
+	if pc.Android != nil && pc.Android.Ups != nil {
+		ups := pc.Android.Ups
+		channel.Notification = ups.Notification
+		channel.Transmission = ups.Transmission
+
+		if o, ok := ups.Options[VendorHuawei]; ok {
+			channel.Huawei = &HuaweiOptions{
+				Importance:  optString(o, "/message/android/notification/importance"),
+				Category:    optString(o, "/message/android/category"),
+				ChannelId:   optString(o, "/message/android/notification/channel_id"),
+				BadgeClass:  optString(o, "/message/android/notification/badge/class"),
+				BadgeAddNum: optInt(o, "/message/android/notification/badge/add_num"),
+			}
+		}
+		if o, ok := ups.Options[VendorXiaomi]; ok {
+			channel.Xiaomi = &XiaomiOptions{ChannelId: optString(o, "/extra.channel_id")}
+		}
+		if o, ok := ups.Options[VendorOppo]; ok {
+			channel.Oppo = &OppoOptions{
+				ChannelId:   optString(o, "/channel_id"),
+				Category:    optString(o, "/category"),
+				NotifyLevel: optInt(o, "/notify_level"),
+			}
+		}
+		if o, ok := ups.Options[VendorVivo]; ok {
+			channel.Vivo = &VivoOptions{
+				Classification: optInt(o, "/classification"),
+				Category:       optString(o, "/category"),
+			}
+		}
+		if o, ok := ups.Options[VendorMeizu]; ok {
+			channel.Meizu = &MeizuOptions{NoticeMsgType: optInt(o, "/noticeMsgType")}
+		}
+	}
+
+	if h := pc.Harmony; h != nil {
+		channel.Harmony = &HarmonyOptions{}
+		if n := h.Notification; n != nil {
+			channel.Harmony.Category = n.Category
+			channel.Harmony.Want = n.Want
+			channel.Harmony.BadgeAddNum = n.BadgeAddNum
+		}
+	}
+
+	*vc = channel
+	return nil
+}
+
+// 读取厂商参数中的字符串
+func optString(options map[string]interface{}, key string) string {
+	value, _ := options[key].(string)
+	return value
+}
+
+// 读取厂商参数中的整数，json解析后为float64
+func optInt(options map[string]interface{}, key string) int {
+	value, _ := options[key].(float64)
+	return int(value)
+}
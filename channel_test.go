@@ -0,0 +1,128 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func newVendorChannel() *VendorChannel {
+	return &VendorChannel{
+		Notification: &UpsNotificationV2{
+			Title:     "厂商通道标题",
+			Body:      "厂商通道内容",
+			ClickType: ClickTypeIntent,
+			Intent:    "intent:#Intent;component=com.example/.MainActivity;end",
+		},
+		Huawei:  &HuaweiOptions{Importance: HuaweiImportanceNormal, Category: "IM", BadgeClass: "com.example.MainActivity", BadgeAddNum: 1},
+		Xiaomi:  &XiaomiOptions{ChannelId: "high_system"},
+		Oppo:    &OppoOptions{ChannelId: "im", Category: "IM", NotifyLevel: OppoNotifyLevelHeadsUp},
+		Vivo:    &VivoOptions{Classification: VivoClassificationSystem, Category: "IM"},
+		Meizu:   &MeizuOptions{NoticeMsgType: 1},
+		Harmony: &HarmonyOptions{Category: "IM", Want: `{"abilityName":"EntryAbility"}`},
+	}
+}
+
+func TestVendorChannel_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(newVendorChannel())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Android struct {
+			Ups struct {
+				Notification map[string]interface{}            `json:"notification"`
+				Options      map[string]map[string]interface{} `json:"options"`
+			} `json:"ups"`
+		} `json:"android"`
+		Harmony struct {
+			Notification map[string]interface{} `json:"notification"`
+		} `json:"harmony"`
+	}
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]interface{}{
+		VendorHuawei: {
+			"/message/android/notification/importance":    "NORMAL",
+			"/message/android/category":                   "IM",
+			"/message/android/notification/badge/class":   "com.example.MainActivity",
+			"/message/android/notification/badge/add_num": float64(1),
+		},
+		VendorXiaomi: {"/extra.channel_id": "high_system"},
+		VendorOppo:   {"/channel_id": "im", "/category": "IM", "/notify_level": float64(16)},
+		VendorVivo:   {"/classification": float64(1), "/category": "IM"},
+		VendorMeizu:  {"/noticeMsgType": float64(1)},
+	}
+	if !reflect.DeepEqual(got.Android.Ups.Options, want) {
+		t.Fatalf("unexpected options %v", got.Android.Ups.Options)
+	}
+	if got.Android.Ups.Notification["click_type"] != ClickTypeIntent {
+		t.Fatalf("unexpected notification %v", got.Android.Ups.Notification)
+	}
+	if got.Harmony.Notification["click_type"] != "want" || got.Harmony.Notification["category"] != "IM" {
+		t.Fatalf("unexpected harmony notification %v", got.Harmony.Notification)
+	}
+}
+
+func TestVendorChannel_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(vc *VendorChannel)
+	}{
+		{"notification and transmission", func(vc *VendorChannel) { vc.Transmission = "data" }},
+		{"unsupported click type", func(vc *VendorChannel) { vc.Notification.ClickType = ClickTypePayloadCustom }},
+		{"huawei normal marketing", func(vc *VendorChannel) { vc.Huawei.Category = "MARKETING" }},
+		{"huawei badge without class", func(vc *VendorChannel) { vc.Huawei.BadgeClass = "" }},
+		{"oppo heads-up marketing", func(vc *VendorChannel) { vc.Oppo.Category = "NEWS" }},
+		{"vivo system marketing", func(vc *VendorChannel) { vc.Vivo.Category = "MARKETING" }},
+		{"vivo marketing service", func(vc *VendorChannel) { vc.Vivo.Classification = VivoClassificationMarketing }},
+		{"meizu unknown type", func(vc *VendorChannel) { vc.Meizu.NoticeMsgType = 2 }},
+		{"harmony url", func(vc *VendorChannel) {
+			vc.Notification.ClickType = ClickTypeUrl
+			vc.Notification.Url = "https://example.com"
+		}},
+		{"transmission with importance", func(vc *VendorChannel) {
+			vc.Notification = nil
+			vc.Transmission = "data"
+			vc.Oppo, vc.Harmony = nil, nil
+		}},
+	}
+
+	if err := newVendorChannel().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		vc := newVendorChannel()
+		tt.modify(vc)
+		if err := vc.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestPushChannelV2_SetVendor(t *testing.T) {
+	client := getClientV2(t)
+
+	req := newPushRequestV2("SetVendor")
+	req.Audience = &AudienceV2{Cid: []string{testCid}}
+	req.PushChannel = &PushChannelV2{Ios: &IosChannelV2{Type: "notify"}}
+
+	channel := newVendorChannel()
+	channel.Huawei.Importance = "HIGH"
+	if err := req.PushChannel.SetVendor(channel); err == nil || req.PushChannel.Android != nil {
+		t.Fatal("expected error for unsupported huawei importance")
+	}
+
+	if err := req.PushChannel.SetVendor(newVendorChannel()); err != nil {
+		t.Fatal(err)
+	}
+	if req.PushChannel.Ios == nil || req.PushChannel.Android.Ups.Options[VendorHuawei] == nil || req.PushChannel.Harmony == nil {
+		t.Fatalf("unexpected push channel %+v", req.PushChannel)
+	}
+	if _, err := client.SinglePushCid(req); err != nil {
+		t.Fatal(err)
+	}
+}
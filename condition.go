@@ -0,0 +1,296 @@
+package GeTuiGo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 筛选条件类型名称
+const (
+	ConditionRegion    = "region"    // 省市编码
+	ConditionPhoneType = "phonetype" // 手机类型
+	ConditionTag       = "tag"       // 用户标签
+)
+
+// 筛选参数的组合方式
+const (
+	OptOr  = 0 // 满足任意一个参数
+	OptAnd = 1 // 满足所有参数
+	OptNot = 2 // 不满足任何参数，相当于 not in
+)
+
+// 手机类型
+const (
+	PhoneTypeAndroid = "ANDROID"
+	PhoneTypeIOS     = "IOS"
+)
+
+// 按省市筛选，满足任意一个编码，编码可以使用 FindRegions 查询，不在编码表中时检查不通过
+func Region(codes ...string) Condition {
+	return Condition{Key: ConditionRegion, Values: codes, OptType: OptOr}
+}
+
+// 按手机类型筛选，满足任意一个类型，使用 PhoneTypeXXX 常量
+func PhoneType(types ...string) Condition {
+	return Condition{Key: ConditionPhoneType, Values: types, OptType: OptOr}
+}
+
+// 按用户标签筛选，满足任意一个标签
+func Tag(tags ...string) Condition {
+	return Condition{Key: ConditionTag, Values: tags, OptType: OptOr}
+}
+
+// 改为满足任意一个参数
+func (cond Condition) Or() Condition {
+	cond.OptType = OptOr
+	return cond
+}
+
+// 改为满足所有参数，如 Tag("vip", "active").And()
+func (cond Condition) And() Condition {
+	cond.OptType = OptAnd
+	return cond
+}
+
+// 改为不满足任何参数，如 PhoneType(PhoneTypeIOS).Not()
+func (cond Condition) Not() Condition {
+	cond.OptType = OptNot
+	return cond
+}
+
+// 检查筛选条件，返回的错误为 ValidationErrors
+func (cond Condition) Validate() error {
+	var v validator
+	switch cond.Key {
+	case ConditionTag:
+	case ConditionRegion:
+		for _, value := range cond.Values {
+			_, ok := LookupRegion(value)
+			v.check(ok || value == "", "values", "unknown region code %q", value)
+		}
+	case ConditionPhoneType:
+		for _, value := range cond.Values {
+			v.check(value == PhoneTypeAndroid || value == PhoneTypeIOS, "values",
+				"unsupported phone type %q, must be %s or %s", value, PhoneTypeAndroid, PhoneTypeIOS)
+		}
+	default:
+		v.add("key", "unsupported key %q", cond.Key)
+	}
+	v.check(len(cond.Values) > 0, "values", "is required")
+	for _, value := range cond.Values {
+		v.check(value != "", "values", "must not contain empty values")
+	}
+	v.check(cond.OptType >= OptOr && cond.OptType <= OptNot, "opt_type", "must be 0, 1 or 2")
+	return v.err()
+}
+
+// 解析筛选条件表达式，结果可以传给 Push.AppendCondition 或 QueryUserCount
+//  示例：tag:vip AND region:11000000 AND NOT phonetype:IOS
+//
+//  语法：
+//  key:value	单个条件，key为region、phonetype或tag，value包含空格或括号时使用双引号
+//  	region的值可以是编码、省市名称或拼音，如 region:11000000、region:杭州、region:hangzhou
+//  OR	同一个key的多个值满足任意一个，如 tag:a OR tag:b，优先级高于AND
+//  AND	同时满足多个条件，对应多个Condition
+//  NOT	不满足后面的条件，如 NOT (tag:a OR tag:b)
+//  关键字不区分大小写，可以使用括号分组
+func ParseConditions(expr string) ([]Condition, error) {
+	tokens, err := lexConditions(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionParser{expr: expr, tokens: tokens}
+
+	var conds []Condition
+	for {
+		cond, err := p.clause()
+		if err != nil {
+			return nil, err
+		}
+		conds = mergeCondition(conds, cond)
+
+		if p.done() {
+			break
+		}
+		if !p.keyword("AND") {
+			return nil, p.errorf("expected AND")
+		}
+	}
+	return conds, nil
+}
+
+// AND连接的单值条件合并到同一个key的条件中
+func mergeCondition(conds []Condition, cond Condition) []Condition {
+	if len(cond.Values) == 1 {
+		for i := range conds {
+			prev := &conds[i]
+			if prev.Key != cond.Key {
+				continue
+			}
+			switch {
+			case cond.OptType == OptNot && prev.OptType == OptNot:
+				// NOT a AND NOT b 即 not in {a, b}
+			case cond.OptType != OptNot && (prev.OptType == OptAnd || prev.OptType == OptOr && len(prev.Values) == 1):
+				prev.OptType = OptAnd
+			default:
+				continue
+			}
+			prev.Values = append(prev.Values, cond.Values[0])
+			return conds
+		}
+	}
+	return append(conds, cond)
+}
+
+// 表达式中的词，pos为在表达式中的字节位置
+type conditionToken struct {
+	text   string
+	pos    int
+	quoted bool // 带引号的值不作为关键字和括号
+}
+
+func lexConditions(expr string) (tokens []conditionToken, err error) {
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, conditionToken{text: string(c), pos: i})
+			i++
+		default:
+			start := i
+			var b strings.Builder
+			quoted := false
+			for i < len(expr) && !strings.ContainsRune(" \t\n\r()", rune(expr[i])) {
+				if expr[i] != '"' {
+					b.WriteByte(expr[i])
+					i++
+					continue
+				}
+				end := strings.IndexByte(expr[i+1:], '"')
+				if end < 0 {
+					return nil, fmt.Errorf("getui: condition %q: unterminated quote at position %d", expr, i)
+				}
+				b.WriteString(expr[i+1 : i+1+end])
+				i += end + 2
+				quoted = true
+			}
+			tokens = append(tokens, conditionToken{text: b.String(), pos: start, quoted: quoted})
+		}
+	}
+	return
+}
+
+type conditionParser struct {
+	expr   string
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *conditionParser) errorf(format string, a ...interface{}) error {
+	at := len(p.expr)
+	if !p.done() {
+		at = p.tokens[p.pos].pos
+	}
+	return fmt.Errorf("getui: condition %q: %s at position %d", p.expr, fmt.Sprintf(format, a...), at)
+}
+
+// 下一个词是指定的符号或关键字时跳过并返回true
+func (p *conditionParser) keyword(word string) bool {
+	if p.done() || p.tokens[p.pos].quoted || !strings.EqualFold(p.tokens[p.pos].text, word) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+// clause = [NOT] group
+func (p *conditionParser) clause() (Condition, error) {
+	not := p.keyword("NOT")
+	cond, err := p.group()
+	if err != nil {
+		return cond, err
+	}
+	if not {
+		cond.OptType = OptNot
+	}
+	return cond, nil
+}
+
+// group = "(" group ")" | predicate { OR predicate }
+func (p *conditionParser) group() (cond Condition, err error) {
+	if p.keyword("(") {
+		if cond, err = p.group(); err != nil {
+			return
+		}
+		if !p.keyword(")") {
+			return cond, p.errorf("expected )")
+		}
+		return
+	}
+
+	if cond, err = p.predicate(); err != nil {
+		return
+	}
+	for p.keyword("OR") {
+		start := p.pos
+		next, err := p.predicate()
+		if err != nil {
+			return cond, err
+		}
+		if next.Key != cond.Key {
+			p.pos = start
+			return cond, p.errorf("OR can only combine values of the same key, got %s and %s", cond.Key, next.Key)
+		}
+		cond.Values = append(cond.Values, next.Values...)
+	}
+	return
+}
+
+// predicate = key ":" value
+func (p *conditionParser) predicate() (Condition, error) {
+	if p.done() {
+		return Condition{}, p.errorf("expected key:value")
+	}
+	token := p.tokens[p.pos]
+	i := strings.IndexByte(token.text, ':')
+	if token.text == "(" || token.text == ")" || i <= 0 || i == len(token.text)-1 {
+		return Condition{}, p.errorf("expected key:value, got %q", token.text)
+	}
+
+	key, value := strings.ToLower(token.text[:i]), token.text[i+1:]
+	switch key {
+	case ConditionTag:
+	case ConditionRegion:
+		regions := FindRegions(value)
+		if len(regions) != 1 {
+			return Condition{}, p.regionError(value, regions)
+		}
+		value = regions[0].Code
+	case ConditionPhoneType:
+		value = strings.ToUpper(value)
+		if value != PhoneTypeAndroid && value != PhoneTypeIOS {
+			return Condition{}, p.errorf("unsupported phone type %q", value)
+		}
+	default:
+		return Condition{}, p.errorf("unknown key %q", key)
+	}
+	p.pos++
+	return Condition{Key: key, Values: []string{value}, OptType: OptOr}, nil
+}
+
+func (p *conditionParser) regionError(value string, regions []*RegionInfo) error {
+	if len(regions) == 0 {
+		return p.errorf("unknown region %q", value)
+	}
+	names := make([]string, len(regions))
+	for i, r := range regions {
+		names[i] = r.Name + " " + r.Code
+	}
+	return p.errorf("ambiguous region %q, use one of the codes: %s", value, strings.Join(names, ", "))
+}
@@ -0,0 +1,112 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConditions(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []Condition
+	}{
+		{
+			"tag:vip AND region:11000000 AND NOT phonetype:IOS",
+			[]Condition{Tag("vip"), Region("11000000"), PhoneType(PhoneTypeIOS).Not()},
+		},
+		{
+			"(tag:a OR tag:b) and not (region:11000000 or region:31000000)",
+			[]Condition{Tag("a", "b"), Region("11000000", "31000000").Not()},
+		},
+		{
+			"tag:a AND tag:b AND NOT tag:c AND NOT tag:d AND phonetype:android",
+			[]Condition{Tag("a", "b").And(), Tag("c", "d").Not(), PhoneType(PhoneTypeAndroid)},
+		},
+		{
+			`tag:"new user" AND tag:"AND"`,
+			[]Condition{Tag("new user", "AND").And()},
+		},
+	}
+
+	for _, tt := range tests {
+		conds, err := ParseConditions(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(conds, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.expr, conds, tt.want)
+		}
+	}
+}
+
+func TestParseConditions_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		msg  string
+	}{
+		{"", "expected key:value"},
+		{"tag:vip region:11000000", "expected AND"},
+		{"tag:vip OR region:11000000", "same key"},
+		{"phontype:IOS", "unknown key"},
+		{"phonetype:WINDOWS", "unsupported phone type"},
+		{"region:beijin", "unknown region"},
+		{"region:suzhou", "ambiguous region"},
+		{"(tag:vip", "expected )"},
+		{`tag:"vip`, "unterminated quote"},
+		{"NOT", "expected key:value"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseConditions(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.expr, tt.msg, err)
+		}
+	}
+}
+
+func TestCondition_Validate(t *testing.T) {
+	invalid := []Condition{
+		{Key: "phonetype", Values: []string{"ios"}},
+		{Key: "city", Values: []string{"1"}},
+		Tag(),
+		{Key: ConditionTag, Values: []string{"vip"}, OptType: 3},
+	}
+	for _, cond := range invalid {
+		if err := cond.Validate(); !errors.Is(err, ErrInvalidParam) {
+			t.Errorf("%+v: expected invalid param error, got %v", cond, err)
+		}
+	}
+
+	push := &Push{Template: TmplTransmission{TransmissionContent: "condition"}}
+	push.AppendCondition(Tag("vip"), PhoneType("ios"))
+	if err := push.Validate(); err == nil || !strings.Contains(err.Error(), "condition[1].values") {
+		t.Fatalf("expected condition error, got %v", err)
+	}
+}
+
+func TestClient_QueryUserCount(t *testing.T) {
+	client := getClient(t)
+
+	if _, err := client.SetTags(testCid, []string{"cond-vip", "cond-active"}); err != nil {
+		t.Fatal(err)
+	}
+	conds, err := ParseConditions("tag:cond-vip AND tag:cond-active AND NOT tag:cond-blocked")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, count, err := client.QueryUserCount(conds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != ResultOk || count != 1 {
+		t.Fatalf("unexpected result %s, count %d", result, count)
+	}
+
+	if _, _, err = client.QueryUserCount(); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected invalid param error, got %v", err)
+	}
+}
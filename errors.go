@@ -0,0 +1,114 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 各响应结果对应的错误，可使用 errors.Is(err, ErrXXX) 判断
+var (
+	ErrNoMsg              = errors.New(ResultNoMsg)
+	ErrAliasError         = errors.New(ResultAliasError)
+	ErrBlackIp            = errors.New(ResultBlackIp)
+	ErrSignError          = errors.New(ResultSignError)
+	ErrPushNumOverLimit   = errors.New(ResultPushNumOverLimit)
+	ErrNoAppid            = errors.New(ResultNoAppid)
+	ErrNoUser             = errors.New(ResultNoUser)
+	ErrTooFrequent        = errors.New(ResultTooFrequent)
+	ErrSensitiveWord      = errors.New(ResultSensitiveWord)
+	ErrAppidNotMatch      = errors.New(ResultAppidNotMatch)
+	ErrNotAuth            = errors.New(ResultNotAuth)
+	ErrBlackAppId         = errors.New(ResultBlackAppId)
+	ErrInvalidParam       = errors.New(ResultInvalidParam)
+	ErrAliasNotBind       = errors.New(ResultAliasNotBind)
+	ErrTagOverLimit       = errors.New(ResultTagOverLimit)
+	ErrTagInvalidOrNoAuth = errors.New(ResultTagInvalidOrNoAuth)
+	ErrNoValidPush        = errors.New(ResultNoValidPush)
+	ErrNoTaskId           = errors.New(ResultNoTaskId)
+	ErrOtherError         = errors.New(ResultOtherError)
+)
+
+var resultErrors = map[string]error{
+	ResultNoMsg:              ErrNoMsg,
+	ResultAliasError:         ErrAliasError,
+	ResultBlackIp:            ErrBlackIp,
+	ResultSignError:          ErrSignError,
+	ResultPushNumOverLimit:   ErrPushNumOverLimit,
+	ResultNoAppid:            ErrNoAppid,
+	ResultNoUser:             ErrNoUser,
+	ResultTooFrequent:        ErrTooFrequent,
+	ResultSensitiveWord:      ErrSensitiveWord,
+	ResultAppidNotMatch:      ErrAppidNotMatch,
+	ResultNotAuth:            ErrNotAuth,
+	ResultBlackAppId:         ErrBlackAppId,
+	ResultInvalidParam:       ErrInvalidParam,
+	ResultAliasNotBind:       ErrAliasNotBind,
+	ResultTagOverLimit:       ErrTagOverLimit,
+	ResultTagInvalidOrNoAuth: ErrTagInvalidOrNoAuth,
+	ResultNoValidPush:        ErrNoValidPush,
+	ResultNoTaskId:           ErrNoTaskId,
+	ResultOtherError:         ErrOtherError,
+}
+
+// v2接口错误码对应的错误
+var codeErrors = map[int]error{
+	CodeV2TokenInvalid:    ErrNotAuth,
+	CodeV2BlackList:       ErrBlackAppId,
+	CodeV2AuthTooFrequent: ErrTooFrequent,
+	CodeV2TooFrequent:     ErrTooFrequent,
+	CodeV2InvalidParam:    ErrInvalidParam,
+}
+
+// 接口返回的错误，响应结果不是ok或successed_xxx，或http状态码不是2xx时返回
+type APIError struct {
+	Result     string // 响应结果，见 ResultXXX 常量
+	Code       int    // v2接口的错误码，见 CodeV2XXX 常量
+	Desc       string // 错误信息描述
+	StatusCode int    // http状态码
+	Endpoint   string // 接口名称，如 push_single
+	RequestId  string // 推送请求的唯一标识，非推送接口为空
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "getui: %s", e.Endpoint)
+	if e.Result != "" {
+		fmt.Fprintf(&b, " result=%s", e.Result)
+	}
+	if e.Code != 0 {
+		fmt.Fprintf(&b, " code=%d", e.Code)
+	}
+	if e.Desc != "" {
+		fmt.Fprintf(&b, " desc=%q", e.Desc)
+	}
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " status=%d", e.StatusCode)
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(&b, " requestid=%s", e.RequestId)
+	}
+	return b.String()
+}
+
+// 返回响应结果对应的 ErrXXX，使 errors.Is 可以判断错误类型
+func (e *APIError) Unwrap() error {
+	if e.Code != 0 {
+		return codeErrors[e.Code]
+	}
+	return resultErrors[e.Result]
+}
+
+// 响应结果是否表示成功
+func isSuccess(result string) bool {
+	return result == ResultOk || strings.HasPrefix(result, "successed_")
+}
+
+// 为接口错误补充推送请求的唯一标识
+func withRequestId(err error, requestId string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.RequestId = requestId
+	}
+	return err
+}
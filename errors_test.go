@@ -0,0 +1,88 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// 启动测试服务器并创建使用它的客户端，所有请求包括auth_sign都交给handler
+func newStubClient(t *testing.T, handler http.HandlerFunc, opts ...Option) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client, err := NewClient("appId", "appKey", "secret", append([]Option{WithBaseURL(server.URL)}, opts...)...)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+// 同 newStubClient，auth_sign返回固定的token，其余请求交给handler
+func newStubServer(t *testing.T, handler http.HandlerFunc, opts ...Option) (*Client, *httptest.Server) {
+	return newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/appId/auth_sign" {
+			w.Write([]byte(`{"result":"ok","auth_token":"token"}`))
+			return
+		}
+		handler(w, r)
+	}, opts...)
+}
+
+// 所有接口返回固定内容的测试服务器
+func newErrorServer(t *testing.T, statusCode int, body string) (*Client, *httptest.Server) {
+	return newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	})
+}
+
+func TestAPIError_Result(t *testing.T) {
+	client, server := newErrorServer(t, http.StatusOK, `{"result":"no_user","desc":"cid not found"}`)
+	defer server.Close()
+
+	push := &Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid", RequestId: "req1"}
+	result, err := client.SinglePush(push)
+	if !errors.Is(err, ErrNoUser) || errors.Is(err, ErrTooFrequent) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.Endpoint != "push_single" || apiErr.Desc != "cid not found" || apiErr.RequestId != "req1" || apiErr.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected error fields %+v", apiErr)
+	}
+	if result.Result != ResultNoUser {
+		t.Fatalf("unexpected result %s", result.Result)
+	}
+
+	if _, _, err := client.QueryCid("alias"); !errors.Is(err, ErrNoUser) {
+		t.Fatalf("expected ErrNoUser, got %v", err)
+	}
+}
+
+func TestAPIError_HTTPStatus(t *testing.T) {
+	client, server := newErrorServer(t, http.StatusBadGateway, "<html>bad gateway</html>")
+	defer server.Close()
+
+	_, _, err := client.UserStatus("cid")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway || apiErr.Endpoint != "user_status" || apiErr.Result != "" {
+		t.Fatalf("unexpected error fields %+v", apiErr)
+	}
+}
+
+func TestAPIError_Success(t *testing.T) {
+	client, server := newErrorServer(t, http.StatusOK, `{"result":"ok","taskid":"task","status":"successed_offline"}`)
+	defer server.Close()
+
+	result, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"})
+	if err != nil || result.Status != ResultSuccessOffline {
+		t.Fatalf("unexpected result %v, %v", result, err)
+	}
+}
@@ -0,0 +1,1021 @@
+// 基于httptest实现的个推v1、v2接口模拟服务，用于离线测试
+//
+//  server := getuitest.NewServer("appId", "appKey", "masterSecret")
+//  defer server.Close()
+//  server.AddUser("cid", true)
+//  client, err := GeTuiGo.NewClient("appId", "appKey", "masterSecret", GeTuiGo.WithBaseURL(server.BaseURL()))
+//
+// v2客户端使用 server.BaseURLV2() 作为接口地址
+// 服务端状态保存在内存中，可以通过 Fail 模拟接口错误
+package getuitest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxPushListTargets = 1000 // tolist单次最多推送的目标数
+	maxSinglePushBatch = 200  // 批量单推单次最多的消息数
+	maxAliasCids       = 10   // 一个别名最多绑定的cid数
+	maxBindAlias       = 1000 // 单次最多绑定的别名数
+	maxTags            = 100  // 单个用户最多设置的tag数
+	maxPushResultTasks = 100  // 单次最多查询推送结果的任务数
+
+	timeLayout = "2006-01-02 15:04:05" // 定时下发时间、展示时间段的格式
+)
+
+// 模拟的接口错误
+type Failure struct {
+	Result     string // v1响应结果，如 too_frequent
+	Code       int    // v2错误码，如 10005
+	Desc       string // 错误信息描述
+	StatusCode int    // http状态码，0表示200；未设置Result、Code时返回非json内容
+	Times      int    // 生效次数，0表示一直生效
+}
+
+// 用户
+type User struct {
+	Cid       string
+	Online    bool
+	LastLogin time.Time
+	Tags      []string
+}
+
+// 推送任务统计数据
+type TaskStats struct {
+	MsgTotal   int // 有效可下发总数
+	MsgProcess int // 消息回执总数
+	ClickNum   int // 用户点击数
+	PushNum    int // 下发总量
+}
+
+// 推送任务
+type Task struct {
+	Id       string
+	Endpoint string                 // 创建任务的接口
+	Name     string                 // task_name
+	PushTime string                 // 定时下发时间
+	Body     map[string]interface{} // 消息内容
+	Stopped  bool
+	Stats    TaskStats
+}
+
+// 一条已下发的消息
+type Message struct {
+	Endpoint  string
+	TaskId    string
+	RequestId string
+	Cid       string
+	Status    string                 // successed_online、successed_offline等
+	Body      map[string]interface{} // 消息内容
+}
+
+// 个推接口模拟服务
+type Server struct {
+	*httptest.Server
+	AppId        string
+	AppKey       string
+	MasterSecret string
+
+	mu        sync.Mutex
+	seq       int
+	tokens    map[string]bool
+	users     map[string]*User
+	aliases   map[string][]string // alias -> cid列表
+	blacklist map[string]bool
+	tasks     map[string]*Task
+	requests  map[string]string // requestid -> taskid，用于去重
+	messages  []Message
+	failures  map[string][]*Failure
+	requestN  map[string]int
+}
+
+// 创建并启动模拟服务，使用完毕后需调用Close
+func NewServer(appId, appKey, masterSecret string) *Server {
+	s := &Server{
+		AppId:        appId,
+		AppKey:       appKey,
+		MasterSecret: masterSecret,
+		tokens:       make(map[string]bool),
+		users:        make(map[string]*User),
+		aliases:      make(map[string][]string),
+		blacklist:    make(map[string]bool),
+		tasks:        make(map[string]*Task),
+		requests:     make(map[string]string),
+		failures:     make(map[string][]*Failure),
+		requestN:     make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// 接口地址，传给 GeTuiGo.WithBaseURL
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// 添加用户
+func (s *Server) AddUser(cid string, online bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[cid] = &User{Cid: cid, Online: online, LastLogin: time.Now()}
+}
+
+// 设置用户在线状态
+func (s *Server) SetOnline(cid string, online bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user := s.users[cid]; user != nil {
+		user.Online = online
+		if online {
+			user.LastLogin = time.Now()
+		}
+	}
+}
+
+// 模拟接口错误，endpoint为接口名称，如 push_single、auth_sign、push/single/cid
+//  同一接口设置多个错误时按顺序生效
+func (s *Server) Fail(endpoint string, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := failure
+	s.failures[endpoint] = append(s.failures[endpoint], &f)
+}
+
+// 清除所有模拟的接口错误
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = make(map[string][]*Failure)
+}
+
+// 使已发放的auth_token全部失效，之后的请求返回not_auth
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = make(map[string]bool)
+}
+
+// 接口被请求的次数，包含模拟错误的请求
+func (s *Server) RequestCount(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requestN[endpoint]
+}
+
+// 已下发的消息
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// 下发给某个cid的消息
+func (s *Server) MessagesTo(cid string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []Message
+	for _, m := range s.messages {
+		if m.Cid == cid {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// 查询推送任务
+func (s *Server) Task(taskId string) (Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskId]
+	if !ok {
+		return Task{}, false
+	}
+	return *task, true
+}
+
+// 修改推送任务的统计数据，用于模拟回执、点击等
+func (s *Server) UpdateTask(taskId string, update func(stats *TaskStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if task := s.tasks[taskId]; task != nil {
+		update(&task.Stats)
+	}
+}
+
+// 别名绑定的cid
+func (s *Server) AliasCids(alias string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.aliases[alias]...)
+}
+
+// cid是否在黑名单中
+func (s *Server) Blacklisted(cid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.blacklist[cid]
+}
+
+type response map[string]interface{}
+
+func resultResponse(result, desc string) response {
+	resp := response{"result": result}
+	if desc != "" {
+		resp["desc"] = desc
+	}
+	return resp
+}
+
+// 路由：/v1/{appId}/{endpoint}[/{arg}]
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/v2/") {
+		s.serveV2(w, r)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/"), "/", 3)
+	if len(parts) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+	appId, endpoint, arg := parts[0], parts[1], ""
+	if len(parts) == 3 {
+		arg = parts[2]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requestN[endpoint]++
+	if s.fail(w, endpoint) {
+		return
+	}
+
+	var resp response
+	switch {
+	case appId != s.AppId:
+		resp = resultResponse("no_appid", "")
+	case endpoint == "auth_sign":
+		resp = s.authSign(r)
+	case !s.tokens[r.Header.Get("authtoken")]:
+		resp = resultResponse("not_auth", "")
+	default:
+		handler, ok := s.handlers()[r.Method+" "+endpoint]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		resp = handler(r, arg)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// 返回模拟的接口错误，没有时返回false
+func (s *Server) fail(w http.ResponseWriter, endpoint string) bool {
+	failures := s.failures[endpoint]
+	if len(failures) == 0 {
+		return false
+	}
+
+	f := failures[0]
+	if f.Times > 0 {
+		if f.Times--; f.Times == 0 {
+			s.failures[endpoint] = failures[1:]
+		}
+	}
+
+	statusCode := f.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	if f.Result == "" {
+		w.WriteHeader(statusCode)
+		fmt.Fprint(w, http.StatusText(statusCode))
+		return true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resultResponse(f.Result, f.Desc))
+	return true
+}
+
+func (s *Server) handlers() map[string]func(r *http.Request, arg string) response {
+	return map[string]func(r *http.Request, arg string) response{
+		"POST push_single":                   s.pushSingle,
+		"POST push_single_batch":             s.pushSingleBatch,
+		"POST save_list_body":                s.saveListBody,
+		"POST push_list":                     s.pushList,
+		"POST push_app":                      s.pushApp,
+		"DELETE stop_task":                   s.stopTask,
+		"POST get_schedule_task":             s.getScheduleTask,
+		"POST del_schedule_task":             s.delScheduleTask,
+		"POST bind_alias":                    s.bindAlias,
+		"POST unbind_alias":                  s.unbindAlias,
+		"POST unbind_alias_all":              s.unbindAliasAll,
+		"GET query_cid":                      s.queryCid,
+		"GET query_alias":                    s.queryAlias,
+		"POST set_tags":                      s.setTags,
+		"GET get_tags":                       s.getTags,
+		"POST user_blk_list":                 s.addBlackList,
+		"DELETE user_blk_list":               s.removeBlackList,
+		"GET user_status":                    s.userStatus,
+		"POST push_result":                   s.pushResult,
+		"POST get_push_result_by_group_name": s.pushResultByGroup,
+		"POST query_app_push":                s.queryAppPush,
+		"POST set_badge":                     s.setBadge,
+		"POST query_user_count":              s.queryUserCount,
+		"POST query_bi_tags":                 s.queryBiTags,
+	}
+}
+
+func decode(r *http.Request, v interface{}) bool {
+	return json.NewDecoder(r.Body).Decode(v) == nil
+}
+
+func (s *Server) authSign(r *http.Request) response {
+	var body struct {
+		Sign      string `json:"sign"`
+		Timestamp string `json:"timestamp"`
+		AppKey    string `json:"appkey"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+
+	sign := fmt.Sprintf("%x", sha256.Sum256([]byte(body.AppKey+body.Timestamp+s.MasterSecret)))
+	if body.AppKey != s.AppKey || body.Sign != sign {
+		return resultResponse("sign_error", "")
+	}
+
+	token := s.nextId("token")
+	s.tokens[token] = true
+	expireTime := time.Now().Add(24*time.Hour).UnixNano() / int64(time.Millisecond)
+	return response{"result": "ok", "auth_token": token, "expire_time": fmt.Sprint(expireTime)}
+}
+
+func (s *Server) nextId(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%d", prefix, s.seq)
+}
+
+// 检查消息体并创建任务
+func (s *Server) newTask(endpoint string, body map[string]interface{}) (*Task, response) {
+	message, _ := body["message"].(map[string]interface{})
+	if message == nil {
+		return nil, resultResponse("no_msg", "")
+	}
+	if message["appkey"] != s.AppKey {
+		return nil, resultResponse("appid_notmatch", "")
+	}
+
+	// 时间参数格式为yyyy-MM-dd HH:mm:ss
+	for _, key := range []string{"push_time", "duration_begin", "duration_end"} {
+		if value, ok := body[key].(string); ok {
+			if _, err := time.Parse(timeLayout, value); err != nil {
+				return nil, resultResponse("invalid_param", key+" must be formatted as yyyy-MM-dd HH:mm:ss")
+			}
+		}
+	}
+
+	task := &Task{Id: s.nextId("task"), Endpoint: endpoint, Body: body}
+	task.Name, _ = body["task_name"].(string)
+	task.PushTime, _ = body["push_time"].(string)
+	s.tasks[task.Id] = task
+	return task, nil
+}
+
+// 下发消息给cid，返回推送状态
+func (s *Server) deliver(task *Task, requestId, cid string) string {
+	user := s.users[cid]
+	if user == nil {
+		return "no_user"
+	}
+
+	status := "successed_offline"
+	switch {
+	case s.blacklist[cid]:
+		status = "successed_ignore"
+	case user.Online:
+		status = "successed_online"
+	}
+
+	if status != "successed_ignore" {
+		task.Stats.MsgTotal++
+		task.Stats.PushNum++
+		if user.Online {
+			task.Stats.MsgProcess++
+		}
+	}
+	s.messages = append(s.messages, Message{
+		Endpoint:  task.Endpoint,
+		TaskId:    task.Id,
+		RequestId: requestId,
+		Cid:       cid,
+		Status:    status,
+		Body:      task.Body,
+	})
+	return status
+}
+
+// 单推，同一requestid只下发一次
+func (s *Server) pushOne(endpoint string, body map[string]interface{}) response {
+	requestId, _ := body["requestid"].(string)
+	if taskId, ok := s.requests[requestId]; ok && requestId != "" {
+		return response{"result": "ok", "taskid": taskId, "status": s.lastStatus(taskId)}
+	}
+
+	var cids []string
+	if cid, _ := body["cid"].(string); cid != "" {
+		cids = []string{cid}
+	} else if alias, _ := body["alias"].(string); alias != "" {
+		if cids = s.aliases[alias]; len(cids) == 0 {
+			return resultResponse("alias_notbind", "")
+		}
+	} else {
+		return resultResponse("invalid_param", "cid or alias is required")
+	}
+
+	for _, cid := range cids {
+		if s.users[cid] == nil {
+			return resultResponse("no_user", "")
+		}
+	}
+
+	task, errResp := s.newTask(endpoint, body)
+	if errResp != nil {
+		return errResp
+	}
+	if requestId != "" {
+		s.requests[requestId] = task.Id
+	}
+
+	status := ""
+	for _, cid := range cids {
+		status = s.deliver(task, requestId, cid)
+	}
+	return response{"result": "ok", "taskid": task.Id, "status": status}
+}
+
+func (s *Server) lastStatus(taskId string) string {
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if s.messages[i].TaskId == taskId {
+			return s.messages[i].Status
+		}
+	}
+	return ""
+}
+
+func (s *Server) pushSingle(r *http.Request, arg string) response {
+	var body map[string]interface{}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+	return s.pushOne("push_single", body)
+}
+
+func (s *Server) pushSingleBatch(r *http.Request, arg string) response {
+	var body struct {
+		MsgList    []map[string]interface{} `json:"msg_list"`
+		NeedDetail bool                     `json:"need_detail"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+	if len(body.MsgList) > maxSinglePushBatch {
+		return resultResponse("invalid_param", fmt.Sprintf("at most %d messages", maxSinglePushBatch))
+	}
+
+	details := make([]response, 0, len(body.MsgList))
+	for _, msg := range body.MsgList {
+		resp := s.pushOne("push_single_batch", msg)
+		detail := response{"cid": msg["cid"], "requestid": msg["requestid"], "taskid": resp["taskid"], "status": resp["status"]}
+		if resp["result"] != "ok" {
+			detail["status"] = resp["result"]
+		}
+		details = append(details, detail)
+	}
+
+	resp := response{"result": "ok"}
+	if body.NeedDetail {
+		resp["details"] = details
+	}
+	return resp
+}
+
+func (s *Server) saveListBody(r *http.Request, arg string) response {
+	var body map[string]interface{}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+
+	task, errResp := s.newTask("save_list_body", body)
+	if errResp != nil {
+		return errResp
+	}
+	return response{"result": "ok", "taskid": task.Id}
+}
+
+func (s *Server) pushList(r *http.Request, arg string) response {
+	var body struct {
+		Cid        []string `json:"cid"`
+		TaskId     string   `json:"taskid"`
+		Alias      []string `json:"alias"`
+		NeedDetail bool     `json:"need_detail"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+
+	task := s.tasks[body.TaskId]
+	if task == nil || task.Endpoint != "save_list_body" {
+		return resultResponse("no_taskid", "")
+	}
+	if len(body.Cid) > maxPushListTargets || len(body.Alias) > maxPushListTargets {
+		return resultResponse("invalid_param", fmt.Sprintf("at most %d targets", maxPushListTargets))
+	}
+
+	resp := response{"result": "ok", "taskid": task.Id}
+	if len(body.Cid) > 0 {
+		details := make(map[string]string, len(body.Cid))
+		for _, cid := range body.Cid {
+			details[cid] = s.deliver(task, "", cid)
+		}
+		if body.NeedDetail {
+			resp["cid_details"] = details
+		}
+		return resp
+	}
+
+	details := make(map[string]string, len(body.Alias))
+	for _, alias := range body.Alias {
+		cids := s.aliases[alias]
+		if len(cids) == 0 {
+			details[alias] = "alias_notbind"
+			continue
+		}
+		for _, cid := range cids {
+			details[alias] = s.deliver(task, "", cid)
+		}
+	}
+	if body.NeedDetail {
+		resp["alias_details"] = details
+	}
+	return resp
+}
+
+// 按条件群推，只支持按tag筛选，其他条件只记录不筛选
+func (s *Server) pushApp(r *http.Request, arg string) response {
+	var body map[string]interface{}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+
+	requestId, _ := body["requestid"].(string)
+	if taskId, ok := s.requests[requestId]; ok && requestId != "" {
+		return response{"result": "ok", "taskid": taskId}
+	}
+
+	task, errResp := s.newTask("push_app", body)
+	if errResp != nil {
+		return errResp
+	}
+	if requestId != "" {
+		s.requests[requestId] = task.Id
+	}
+
+	conditions, _ := body["condition"].([]interface{})
+	for _, cid := range s.sortedCids() {
+		if matchTags(s.users[cid].Tags, conditions) {
+			s.deliver(task, requestId, cid)
+		}
+	}
+	return response{"result": "ok", "taskid": task.Id}
+}
+
+func (s *Server) sortedCids() []string {
+	cids := make([]string, 0, len(s.users))
+	for cid := range s.users {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+	return cids
+}
+
+// 判断用户tag是否满足tag筛选条件
+func matchTags(tags []string, conditions []interface{}) bool {
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	for _, c := range conditions {
+		cond, _ := c.(map[string]interface{})
+		if cond["key"] != "tag" {
+			continue
+		}
+		values, _ := cond["values"].([]interface{})
+		optType, _ := cond["opt_type"].(float64)
+
+		matched := 0
+		for _, v := range values {
+			if tag, _ := v.(string); has[tag] {
+				matched++
+			}
+		}
+		switch optType {
+		case 0:
+			if matched == 0 {
+				return false
+			}
+		case 1:
+			if matched != len(values) {
+				return false
+			}
+		case 2:
+			if matched > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *Server) stopTask(r *http.Request, taskId string) response {
+	task := s.tasks[taskId]
+	if task == nil {
+		return resultResponse("no_taskid", "")
+	}
+	task.Stopped = true
+	return response{"result": "ok", "taskid": taskId}
+}
+
+func (s *Server) scheduleTask(r *http.Request) *Task {
+	var body struct {
+		TaskId string `json:"taskid"`
+	}
+	if !decode(r, &body) {
+		return nil
+	}
+	if task := s.tasks[body.TaskId]; task != nil && task.PushTime != "" {
+		return task
+	}
+	return nil
+}
+
+func (s *Server) getScheduleTask(r *http.Request, arg string) response {
+	task := s.scheduleTask(r)
+	if task == nil {
+		return resultResponse("no_taskid", "")
+	}
+
+	sendResult := "waiting"
+	if task.Stopped {
+		sendResult = "deleted"
+	}
+	return response{
+		"result": "ok",
+		"taskid": task.Id,
+		"taskDetail": response{
+			"pushContent": task.Body,
+			"pushTime":    task.PushTime,
+			"sendResult":  sendResult,
+		},
+	}
+}
+
+func (s *Server) delScheduleTask(r *http.Request, arg string) response {
+	task := s.scheduleTask(r)
+	if task == nil {
+		return resultResponse("no_taskid", "")
+	}
+	task.Stopped = true
+	return response{"result": "ok"}
+}
+
+func (s *Server) bindAlias(r *http.Request, arg string) response {
+	var body struct {
+		AliasList []struct {
+			Cid   string `json:"cid"`
+			Alias string `json:"alias"`
+		} `json:"alias_list"`
+	}
+	if !decode(r, &body) || len(body.AliasList) == 0 {
+		return resultResponse("invalid_param", "")
+	}
+	if len(body.AliasList) > maxBindAlias {
+		return resultResponse("invalid_param", fmt.Sprintf("at most %d aliases", maxBindAlias))
+	}
+
+	for _, item := range body.AliasList {
+		if item.Cid == "" || item.Alias == "" {
+			return resultResponse("invalid_param", "cid and alias are required")
+		}
+	}
+
+	for _, item := range body.AliasList {
+		if contains(s.aliases[item.Alias], item.Cid) {
+			continue
+		}
+		if len(s.aliases[item.Alias]) >= maxAliasCids {
+			return resultResponse("alias_error", fmt.Sprintf("alias %s is bound to %d cids", item.Alias, maxAliasCids))
+		}
+
+		// 一个cid只能绑定一个别名，绑定新别名时与旧别名解绑
+		for alias, cids := range s.aliases {
+			s.aliases[alias] = remove(cids, item.Cid)
+			if len(s.aliases[alias]) == 0 {
+				delete(s.aliases, alias)
+			}
+		}
+		s.aliases[item.Alias] = append(s.aliases[item.Alias], item.Cid)
+	}
+	return response{"result": "ok"}
+}
+
+func (s *Server) unbindAlias(r *http.Request, arg string) response {
+	var body struct {
+		Cid   string `json:"cid"`
+		Alias string `json:"alias"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+	if !contains(s.aliases[body.Alias], body.Cid) {
+		return resultResponse("alias_notbind", "")
+	}
+
+	if s.aliases[body.Alias] = remove(s.aliases[body.Alias], body.Cid); len(s.aliases[body.Alias]) == 0 {
+		delete(s.aliases, body.Alias)
+	}
+	return response{"result": "ok"}
+}
+
+func (s *Server) unbindAliasAll(r *http.Request, arg string) response {
+	var body struct {
+		Alias string `json:"alias"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+	if len(s.aliases[body.Alias]) == 0 {
+		return resultResponse("alias_notbind", "")
+	}
+
+	delete(s.aliases, body.Alias)
+	return response{"result": "ok"}
+}
+
+func (s *Server) queryCid(r *http.Request, alias string) response {
+	cids := s.aliases[alias]
+	if len(cids) == 0 {
+		return resultResponse("alias_notbind", "")
+	}
+	return response{"result": "ok", "cid": cids}
+}
+
+func (s *Server) queryAlias(r *http.Request, cid string) response {
+	for alias, cids := range s.aliases {
+		if contains(cids, cid) {
+			return response{"result": "ok", "alias": alias}
+		}
+	}
+	return resultResponse("alias_notbind", "")
+}
+
+func (s *Server) setTags(r *http.Request, arg string) response {
+	var body struct {
+		Cid     string   `json:"cid"`
+		TagList []string `json:"tag_list"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+
+	user := s.users[body.Cid]
+	if user == nil {
+		return resultResponse("no_user", "")
+	}
+	if len(body.TagList) > maxTags {
+		return resultResponse("tag_over_limit", "")
+	}
+	user.Tags = append([]string(nil), body.TagList...)
+	return response{"result": "ok"}
+}
+
+func (s *Server) getTags(r *http.Request, cid string) response {
+	user := s.users[cid]
+	if user == nil {
+		return resultResponse("no_user", "")
+	}
+	return response{"result": "ok", "tags": strings.Join(user.Tags, ",")}
+}
+
+func (s *Server) blackListBody(r *http.Request) ([]string, bool) {
+	var body struct {
+		Cid []string `json:"cid"`
+	}
+	if !decode(r, &body) || len(body.Cid) == 0 {
+		return nil, false
+	}
+	return body.Cid, true
+}
+
+func (s *Server) addBlackList(r *http.Request, arg string) response {
+	cids, ok := s.blackListBody(r)
+	if !ok {
+		return resultResponse("invalid_param", "")
+	}
+	for _, cid := range cids {
+		s.blacklist[cid] = true
+	}
+	return response{"result": "ok"}
+}
+
+func (s *Server) removeBlackList(r *http.Request, arg string) response {
+	cids, ok := s.blackListBody(r)
+	if !ok {
+		return resultResponse("invalid_param", "")
+	}
+	for _, cid := range cids {
+		delete(s.blacklist, cid)
+	}
+	return response{"result": "ok"}
+}
+
+func (s *Server) userStatus(r *http.Request, cid string) response {
+	user := s.users[cid]
+	if user == nil {
+		return resultResponse("no_user", "")
+	}
+
+	status := "offline"
+	if user.Online {
+		status = "online"
+	}
+	return response{
+		"result":    "ok",
+		"cid":       cid,
+		"status":    status,
+		"lastlogin": fmt.Sprint(user.LastLogin.UnixNano() / int64(time.Millisecond)),
+	}
+}
+
+func taskResult(task *Task) response {
+	return response{
+		"taskid":      task.Id,
+		"msg_total":   task.Stats.MsgTotal,
+		"msg_process": task.Stats.MsgProcess,
+		"click_num":   task.Stats.ClickNum,
+		"push_num":    task.Stats.PushNum,
+		"GT": response{
+			"sent":      task.Stats.PushNum,
+			"feedback":  task.Stats.MsgProcess,
+			"clicked":   task.Stats.ClickNum,
+			"displayed": task.Stats.MsgProcess,
+		},
+	}
+}
+
+func (s *Server) pushResult(r *http.Request, arg string) response {
+	var body struct {
+		TaskIdList []string `json:"taskIdList"`
+	}
+	if !decode(r, &body) || len(body.TaskIdList) == 0 {
+		return resultResponse("invalid_param", "")
+	}
+	if len(body.TaskIdList) > maxPushResultTasks {
+		return resultResponse("invalid_param", fmt.Sprintf("at most %d tasks", maxPushResultTasks))
+	}
+
+	data := make([]response, 0, len(body.TaskIdList))
+	for _, taskId := range body.TaskIdList {
+		if task := s.tasks[taskId]; task != nil {
+			data = append(data, taskResult(task))
+		}
+	}
+	return response{"result": "ok", "data": data}
+}
+
+func (s *Server) pushResultByGroup(r *http.Request, groupName string) response {
+	var stats TaskStats
+	found := false
+	for _, task := range s.tasks {
+		if task.Name == groupName && groupName != "" {
+			found = true
+			stats.MsgTotal += task.Stats.MsgTotal
+			stats.MsgProcess += task.Stats.MsgProcess
+			stats.ClickNum += task.Stats.ClickNum
+			stats.PushNum += task.Stats.PushNum
+		}
+	}
+	if !found {
+		return resultResponse("no_taskid", "")
+	}
+
+	return response{
+		"result":      "ok",
+		"msg_total":   len(s.users),
+		"online_num":  stats.PushNum,
+		"msg_process": stats.MsgProcess,
+		"show_num":    stats.MsgProcess,
+		"click_num":   stats.ClickNum,
+	}
+}
+
+func (s *Server) queryAppPush(r *http.Request, date string) response {
+	if _, err := time.Parse("20060102", date); err != nil {
+		return resultResponse("invalid_param", "date must be formatted as yyyyMMdd")
+	}
+
+	online := 0
+	for _, user := range s.users {
+		if user.Online {
+			online++
+		}
+	}
+	return response{
+		"result": "ok",
+		"data": response{
+			"app_id":             s.AppId,
+			"date":               date,
+			"new_regist_count":   0,
+			"regist_total_count": len(s.users),
+			"active_count":       len(s.users),
+			"online_count":       online,
+		},
+	}
+}
+
+func (s *Server) setBadge(r *http.Request, arg string) response {
+	return response{"result": "ok"}
+}
+
+func (s *Server) queryUserCount(r *http.Request, arg string) response {
+	var body struct {
+		Condition interface{} `json:"condition"`
+	}
+	if !decode(r, &body) {
+		return resultResponse("invalid_param", "")
+	}
+
+	// 兼容单个条件和条件列表
+	conditions, ok := body.Condition.([]interface{})
+	if !ok && body.Condition != nil {
+		conditions = []interface{}{body.Condition}
+	}
+
+	count := 0
+	for _, user := range s.users {
+		if matchTags(user.Tags, conditions) {
+			count++
+		}
+	}
+	return response{"result": "ok", "user_count": count}
+}
+
+func (s *Server) queryBiTags(r *http.Request, arg string) response {
+	return response{"result": "ok", "tags": []string{}}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(list []string, s string) []string {
+	result := list[:0:0]
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
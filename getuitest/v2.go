@@ -0,0 +1,350 @@
+package getuitest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// v2接口错误码
+const (
+	codeSuccess      = 0
+	codeTokenInvalid = 10001
+	codeInvalidParam = 20001
+)
+
+// v2接口地址，传给 GeTuiGo.WithBaseURL
+func (s *Server) BaseURLV2() string {
+	return s.URL + "/v2"
+}
+
+type responseV2 struct {
+	Code int         `json:"code"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+func errorV2(code int, msg string) responseV2 {
+	return responseV2{Code: code, Msg: msg}
+}
+
+func dataV2(data interface{}) responseV2 {
+	return responseV2{Code: codeSuccess, Msg: "success", Data: data}
+}
+
+// 路由：/v2/{appId}/{endpoint}，endpoint如 push/single/cid
+func (s *Server) serveV2(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v2/"), "/", 2)
+	if len(parts) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+	appId, endpoint := parts[0], parts[1]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requestN[endpoint]++
+	if s.failV2(w, endpoint) {
+		return
+	}
+
+	var resp responseV2
+	switch {
+	case appId != s.AppId:
+		resp = errorV2(codeInvalidParam, "appId not found")
+	case endpoint == "auth":
+		resp = s.authV2(r)
+	case !s.tokens[r.Header.Get("token")]:
+		resp = errorV2(codeTokenInvalid, "token invalid")
+	default:
+		handler, ok := s.handlersV2()[r.Method+" "+endpoint]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body map[string]interface{}
+		if json.NewDecoder(r.Body).Decode(&body) != nil {
+			resp = errorV2(codeInvalidParam, "invalid json")
+		} else {
+			resp = handler(body)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// 返回模拟的v2接口错误，没有时返回false
+func (s *Server) failV2(w http.ResponseWriter, endpoint string) bool {
+	failures := s.failures[endpoint]
+	if len(failures) == 0 {
+		return false
+	}
+
+	f := failures[0]
+	if f.Times > 0 {
+		if f.Times--; f.Times == 0 {
+			s.failures[endpoint] = failures[1:]
+		}
+	}
+
+	statusCode := f.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	if f.Code == 0 {
+		w.WriteHeader(statusCode)
+		fmt.Fprint(w, http.StatusText(statusCode))
+		return true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorV2(f.Code, f.Desc))
+	return true
+}
+
+func (s *Server) handlersV2() map[string]func(body map[string]interface{}) responseV2 {
+	return map[string]func(body map[string]interface{}) responseV2{
+		"POST push/single/cid":       s.pushSingleCidV2,
+		"POST push/single/alias":     s.pushSingleAliasV2,
+		"POST push/single/batch/cid": s.pushSingleBatchCidV2,
+		"POST push/list/message":     s.pushListMessageV2,
+		"POST push/list/cid":         s.pushListCidV2,
+		"POST push/all":              s.pushAllV2,
+		"POST push/tag":              s.pushTagV2,
+	}
+}
+
+func (s *Server) authV2(r *http.Request) responseV2 {
+	var body struct {
+		Sign      string `json:"sign"`
+		Timestamp string `json:"timestamp"`
+		AppKey    string `json:"appkey"`
+	}
+	if json.NewDecoder(r.Body).Decode(&body) != nil {
+		return errorV2(codeInvalidParam, "invalid json")
+	}
+
+	sign := fmt.Sprintf("%x", sha256.Sum256([]byte(body.AppKey+body.Timestamp+s.MasterSecret)))
+	if body.AppKey != s.AppKey || body.Sign != sign {
+		return errorV2(codeInvalidParam, "sign error")
+	}
+
+	token := s.nextId("token")
+	s.tokens[token] = true
+	expireTime := time.Now().Add(24*time.Hour).UnixNano() / int64(time.Millisecond)
+	return dataV2(map[string]string{"token": token, "expire_time": fmt.Sprint(expireTime)})
+}
+
+// 检查消息体并创建v2任务
+func (s *Server) newTaskV2(endpoint string, body map[string]interface{}) (*Task, *responseV2) {
+	if _, ok := body["push_message"].(map[string]interface{}); !ok {
+		resp := errorV2(codeInvalidParam, "push_message is required")
+		return nil, &resp
+	}
+
+	task := &Task{Id: s.nextId("task"), Endpoint: endpoint, Body: body}
+	task.Name, _ = body["group_name"].(string)
+	settings, _ := body["settings"].(map[string]interface{})
+	if scheduleTime, ok := settings["schedule_time"].(float64); ok {
+		task.PushTime = fmt.Sprint(int64(scheduleTime))
+	}
+	s.tasks[task.Id] = task
+	return task, nil
+}
+
+// 读取audience中的字符串数组
+func audienceList(body map[string]interface{}, key string) []string {
+	audience, _ := body["audience"].(map[string]interface{})
+	values, _ := audience[key].([]interface{})
+
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			list = append(list, str)
+		}
+	}
+	return list
+}
+
+// v2单推，同一request_id只下发一次
+func (s *Server) pushOneV2(endpoint string, body map[string]interface{}, cids []string) (map[string]map[string]string, *responseV2) {
+	requestId, _ := body["request_id"].(string)
+	if requestId == "" {
+		resp := errorV2(codeInvalidParam, "request_id is required")
+		return nil, &resp
+	}
+	if taskId, ok := s.requests[requestId]; ok {
+		return s.taskStatusV2(taskId), nil
+	}
+
+	task, errResp := s.newTaskV2(endpoint, body)
+	if errResp != nil {
+		return nil, errResp
+	}
+	s.requests[requestId] = task.Id
+
+	result := map[string]string{}
+	for _, cid := range cids {
+		result[cid] = s.deliver(task, requestId, cid)
+	}
+	return map[string]map[string]string{task.Id: result}, nil
+}
+
+// 任务中各cid的推送状态
+func (s *Server) taskStatusV2(taskId string) map[string]map[string]string {
+	result := map[string]string{}
+	for _, m := range s.messages {
+		if m.TaskId == taskId {
+			result[m.Cid] = m.Status
+		}
+	}
+	return map[string]map[string]string{taskId: result}
+}
+
+func (s *Server) pushSingleCidV2(body map[string]interface{}) responseV2 {
+	cids := audienceList(body, "cid")
+	if len(cids) != 1 {
+		return errorV2(codeInvalidParam, "audience.cid must contain exactly one cid")
+	}
+
+	result, errResp := s.pushOneV2("push/single/cid", body, cids)
+	if errResp != nil {
+		return *errResp
+	}
+	return dataV2(result)
+}
+
+func (s *Server) pushSingleAliasV2(body map[string]interface{}) responseV2 {
+	aliases := audienceList(body, "alias")
+	if len(aliases) != 1 {
+		return errorV2(codeInvalidParam, "audience.alias must contain exactly one alias")
+	}
+
+	result, errResp := s.pushOneV2("push/single/alias", body, s.aliases[aliases[0]])
+	if errResp != nil {
+		return *errResp
+	}
+	return dataV2(result)
+}
+
+func (s *Server) pushSingleBatchCidV2(body map[string]interface{}) responseV2 {
+	msgList, _ := body["msg_list"].([]interface{})
+	if len(msgList) == 0 {
+		return errorV2(codeInvalidParam, "msg_list is required")
+	}
+
+	data := map[string]map[string]string{}
+	for _, m := range msgList {
+		msg, _ := m.(map[string]interface{})
+		cids := audienceList(msg, "cid")
+		if len(cids) != 1 {
+			return errorV2(codeInvalidParam, "audience.cid must contain exactly one cid")
+		}
+
+		result, errResp := s.pushOneV2("push/single/batch/cid", msg, cids)
+		if errResp != nil {
+			return *errResp
+		}
+		for taskId, status := range result {
+			data[taskId] = status
+		}
+	}
+
+	if isAsync, _ := body["is_async"].(bool); isAsync {
+		return dataV2(nil)
+	}
+	return dataV2(data)
+}
+
+func (s *Server) pushListMessageV2(body map[string]interface{}) responseV2 {
+	task, errResp := s.newTaskV2("push/list/message", body)
+	if errResp != nil {
+		return *errResp
+	}
+	return dataV2(map[string]string{"taskid": task.Id})
+}
+
+func (s *Server) pushListCidV2(body map[string]interface{}) responseV2 {
+	taskId, _ := body["taskid"].(string)
+	task := s.tasks[taskId]
+	if task == nil || task.Endpoint != "push/list/message" {
+		return errorV2(codeInvalidParam, "taskid not found")
+	}
+
+	cids := audienceList(body, "cid")
+	if len(cids) == 0 || len(cids) > maxPushListTargets {
+		return errorV2(codeInvalidParam, fmt.Sprintf("audience.cid must contain 1 to %d cids", maxPushListTargets))
+	}
+
+	result := map[string]string{}
+	for _, cid := range cids {
+		result[cid] = s.deliver(task, "", cid)
+	}
+
+	if isAsync, _ := body["is_async"].(bool); isAsync {
+		return dataV2(nil)
+	}
+	return dataV2(map[string]map[string]string{task.Id: result})
+}
+
+func (s *Server) pushAllV2(body map[string]interface{}) responseV2 {
+	if body["audience"] != "all" {
+		return errorV2(codeInvalidParam, `audience must be "all"`)
+	}
+	return s.pushCondV2("push/all", body, nil)
+}
+
+// 按条件群推，只支持按custom_tag筛选，其他条件只记录不筛选
+func (s *Server) pushTagV2(body map[string]interface{}) responseV2 {
+	audience, _ := body["audience"].(map[string]interface{})
+	tags, _ := audience["tag"].([]interface{})
+	if len(tags) == 0 {
+		return errorV2(codeInvalidParam, "audience.tag is required")
+	}
+
+	// 转换为v1的条件格式
+	conditions := make([]interface{}, 0, len(tags))
+	for _, t := range tags {
+		tag, _ := t.(map[string]interface{})
+		if tag["key"] != "custom_tag" {
+			continue
+		}
+		optType := map[interface{}]float64{"or": 0, "and": 1, "not": 2}[tag["opt_type"]]
+		conditions = append(conditions, map[string]interface{}{
+			"key":      "tag",
+			"values":   tag["values"],
+			"opt_type": optType,
+		})
+	}
+	return s.pushCondV2("push/tag", body, conditions)
+}
+
+func (s *Server) pushCondV2(endpoint string, body map[string]interface{}, conditions []interface{}) responseV2 {
+	requestId, _ := body["request_id"].(string)
+	if requestId == "" {
+		return errorV2(codeInvalidParam, "request_id is required")
+	}
+	if taskId, ok := s.requests[requestId]; ok {
+		return dataV2(map[string]string{"taskid": taskId})
+	}
+
+	task, errResp := s.newTaskV2(endpoint, body)
+	if errResp != nil {
+		return *errResp
+	}
+	s.requests[requestId] = task.Id
+
+	for _, cid := range s.sortedCids() {
+		if matchTags(s.users[cid].Tags, conditions) {
+			s.deliver(task, requestId, cid)
+		}
+	}
+	return dataV2(map[string]string{"taskid": task.Id})
+}
@@ -0,0 +1,343 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// intent附加参数的类型前缀
+const (
+	ExtraString = "S" // 字符串
+	ExtraInt    = "i" // int，32位
+	ExtraLong   = "l" // long，64位
+	ExtraBool   = "B" // boolean
+	ExtraFloat  = "f" // float，32位
+)
+
+// 安卓intent，对应 Intent.toUri(Intent.URI_INTENT_SCHEME) 的格式：
+//  intent:#Intent;action=xxx;category=xxx;launchFlags=0x10000000;package=包名;component=包名/Activity;S.key=value;end
+//  解析时忽略 type、sourceBounds、SEL 等其他标准字段
+type Intent struct {
+	Component  string        // 包名/Activity全路径，如 com.example/.MainActivity
+	Package    string        // 包名，如 com.example
+	Action     string        // 如 android.intent.action.VIEW
+	Categories []string      // 如 android.intent.category.DEFAULT
+	Data       string        // data uri，如 https://example.com/detail?id=1
+	Flags      int           // launchFlags
+	Extras     []IntentExtra // 附加参数，按添加顺序输出
+}
+
+// intent附加参数
+type IntentExtra struct {
+	Type  string      // 类型前缀，使用 ExtraXXX 常量
+	Key   string      // 参数名
+	Value interface{} // 参数值，类型为 string、int32、int64、bool、float32 之一，与Type对应
+}
+
+// 查找附加参数
+func (intent *Intent) Extra(key string) (IntentExtra, bool) {
+	for _, extra := range intent.Extras {
+		if extra.Key == key {
+			return extra, true
+		}
+	}
+	return IntentExtra{}, false
+}
+
+// 转为intent字符串，超过长度限制时返回错误
+func (intent *Intent) Encode() (string, error) {
+	var b strings.Builder
+	b.WriteString("intent:")
+
+	var scheme string
+	if intent.Data != "" {
+		i := strings.IndexByte(intent.Data, ':')
+		if i <= 0 {
+			return "", fmt.Errorf("getui: intent data %q has no scheme", intent.Data)
+		}
+		scheme = intent.Data[:i]
+		b.WriteString(strings.Replace(intent.Data[i+1:], "#", "%23", -1))
+	}
+
+	b.WriteString("#Intent;")
+	if scheme != "" {
+		writeIntentItem(&b, "scheme", uriEncode(scheme, ""))
+	}
+	if intent.Action != "" {
+		writeIntentItem(&b, "action", uriEncode(intent.Action, ""))
+	}
+	for _, category := range intent.Categories {
+		writeIntentItem(&b, "category", uriEncode(category, ""))
+	}
+	if intent.Flags != 0 {
+		writeIntentItem(&b, "launchFlags", "0x"+strconv.FormatUint(uint64(uint32(intent.Flags)), 16))
+	}
+	if intent.Package != "" {
+		writeIntentItem(&b, "package", uriEncode(intent.Package, ""))
+	}
+	if intent.Component != "" {
+		writeIntentItem(&b, "component", uriEncode(intent.Component, "/"))
+	}
+	for _, extra := range intent.Extras {
+		value, err := formatExtra(extra)
+		if err != nil {
+			return "", err
+		}
+		writeIntentItem(&b, extra.Type+"."+uriEncode(extra.Key, ""), uriEncode(value, ""))
+	}
+	b.WriteString("end")
+
+	str := b.String()
+	if len(str) >= maxIntentLength {
+		return "", fmt.Errorf("getui: intent is %d bytes, must be shorter than %d bytes", len(str), maxIntentLength)
+	}
+	return str, nil
+}
+
+func writeIntentItem(b *strings.Builder, key, value string) {
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte(';')
+}
+
+// 按类型格式化附加参数的值
+func formatExtra(extra IntentExtra) (string, error) {
+	switch v := extra.Value.(type) {
+	case string:
+		if extra.Type == ExtraString {
+			return v, nil
+		}
+	case int32:
+		if extra.Type == ExtraInt {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+	case int64:
+		if extra.Type == ExtraLong {
+			return strconv.FormatInt(v, 10), nil
+		}
+	case bool:
+		if extra.Type == ExtraBool {
+			return strconv.FormatBool(v), nil
+		}
+	case float32:
+		if extra.Type == ExtraFloat {
+			return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+		}
+	}
+	return "", fmt.Errorf("getui: intent extra %q: value %T does not match type %q", extra.Key, extra.Value, extra.Type)
+}
+
+// 按安卓 Uri.encode 的规则转义，字母、数字、_-!.~'()* 和allow中的字符不转义
+func uriEncode(s, allow string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("_-!.~'()*", c) >= 0 || strings.IndexByte(allow, c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// 解析intent字符串
+func ParseIntent(str string) (*Intent, error) {
+	if !strings.HasPrefix(str, "intent:") || !strings.HasSuffix(str, ";end") {
+		return nil, errors.New(`getui: intent must start with "intent:" and end with ";end"`)
+	}
+	i := strings.Index(str, "#Intent;")
+	if i < 0 {
+		return nil, errors.New(`getui: intent has no "#Intent;" section`)
+	}
+
+	intent := &Intent{}
+	data := strings.Replace(str[len("intent:"):i], "%23", "#", -1)
+
+	var scheme string
+	items := strings.Split(str[i+len("#Intent;"):len(str)-len(";end")], ";")
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+		eq := strings.IndexByte(item, '=')
+		if eq < 0 {
+			if strings.IndexByte(item, '.') < 0 {
+				// SEL 等没有值的标准字段
+				continue
+			}
+			return nil, fmt.Errorf("getui: intent item %q has no value", item)
+		}
+		key := item[:eq]
+		value, err := url.PathUnescape(item[eq+1:])
+		if err != nil {
+			return nil, fmt.Errorf("getui: intent item %q: %w", item, err)
+		}
+
+		switch key {
+		case "scheme":
+			scheme = value
+		case "action":
+			intent.Action = value
+		case "category":
+			intent.Categories = append(intent.Categories, value)
+		case "component":
+			intent.Component = value
+		case "package":
+			intent.Package = value
+		case "launchFlags":
+			flags, err := strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("getui: intent launchFlags %q: %w", value, err)
+			}
+			intent.Flags = int(int32(flags))
+		default:
+			if strings.IndexByte(key, '.') < 0 {
+				// type、sourceBounds 等其他标准字段
+				continue
+			}
+			extra, err := parseExtra(key, value)
+			if err != nil {
+				return nil, err
+			}
+			intent.Extras = append(intent.Extras, extra)
+		}
+	}
+
+	if scheme != "" {
+		intent.Data = scheme + ":" + data
+	} else if data != "" {
+		return nil, fmt.Errorf("getui: intent data %q has no scheme", data)
+	}
+	return intent, nil
+}
+
+// 解析附加参数，key为 类型前缀.参数名
+func parseExtra(key, value string) (extra IntentExtra, err error) {
+	dot := strings.IndexByte(key, '.')
+	extra.Type = key[:dot]
+	if extra.Key, err = url.PathUnescape(key[dot+1:]); err != nil {
+		return
+	}
+
+	switch extra.Type {
+	case ExtraString:
+		extra.Value = value
+	case ExtraInt:
+		var v int64
+		v, err = strconv.ParseInt(value, 10, 32)
+		extra.Value = int32(v)
+	case ExtraLong:
+		var v int64
+		v, err = strconv.ParseInt(value, 10, 64)
+		extra.Value = v
+	case ExtraBool:
+		var v bool
+		v, err = strconv.ParseBool(value)
+		extra.Value = v
+	case ExtraFloat:
+		var v float64
+		v, err = strconv.ParseFloat(value, 32)
+		extra.Value = float32(v)
+	default:
+		return extra, fmt.Errorf("getui: unsupported intent extra type %q", extra.Type)
+	}
+	if err != nil {
+		err = fmt.Errorf("getui: intent extra %q: %w", extra.Key, err)
+	}
+	return
+}
+
+// intent构造器
+//  示例：NewIntent("com.example/.MainActivity").String("id", "1").Build()
+type IntentBuilder struct {
+	intent Intent
+	err    error
+}
+
+// 创建intent构造器
+//  component	包名/Activity全路径，如 com.example/.MainActivity
+func NewIntent(component string) *IntentBuilder {
+	return &IntentBuilder{intent: Intent{Component: component}}
+}
+
+func (b *IntentBuilder) Action(action string) *IntentBuilder {
+	b.intent.Action = action
+	return b
+}
+
+// 设置包名，package=包名
+func (b *IntentBuilder) Package(pkg string) *IntentBuilder {
+	b.intent.Package = pkg
+	return b
+}
+
+func (b *IntentBuilder) Category(category string) *IntentBuilder {
+	b.intent.Categories = append(b.intent.Categories, category)
+	return b
+}
+
+// 设置data uri，需要包含scheme
+func (b *IntentBuilder) Data(uri string) *IntentBuilder {
+	b.intent.Data = uri
+	return b
+}
+
+// 设置launchFlags，多个标志按位或
+func (b *IntentBuilder) Flags(flags int) *IntentBuilder {
+	b.intent.Flags |= flags
+	return b
+}
+
+func (b *IntentBuilder) extra(typ, key string, value interface{}) *IntentBuilder {
+	b.intent.Extras = append(b.intent.Extras, IntentExtra{Type: typ, Key: key, Value: value})
+	return b
+}
+
+// 添加字符串参数，S.key=value
+func (b *IntentBuilder) String(key, value string) *IntentBuilder {
+	return b.extra(ExtraString, key, value)
+}
+
+// 添加int参数，i.key=value，超出32位范围时Build返回错误
+func (b *IntentBuilder) Int(key string, value int) *IntentBuilder {
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		if b.err == nil {
+			b.err = fmt.Errorf("getui: intent extra %q: %d overflows int32, use Long", key, value)
+		}
+		return b
+	}
+	return b.extra(ExtraInt, key, int32(value))
+}
+
+// 添加long参数，l.key=value
+func (b *IntentBuilder) Long(key string, value int64) *IntentBuilder {
+	return b.extra(ExtraLong, key, value)
+}
+
+// 添加boolean参数，B.key=value
+func (b *IntentBuilder) Bool(key string, value bool) *IntentBuilder {
+	return b.extra(ExtraBool, key, value)
+}
+
+// 添加float参数，f.key=value
+func (b *IntentBuilder) Float(key string, value float32) *IntentBuilder {
+	return b.extra(ExtraFloat, key, value)
+}
+
+// 生成intent字符串，可直接设置到 TmplStartActivity.Intent
+func (b *IntentBuilder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.intent.Component == "" {
+		return "", errors.New("getui: intent component is required")
+	}
+	return b.intent.Encode()
+}
@@ -0,0 +1,124 @@
+package GeTuiGo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIntentBuilder_Build(t *testing.T) {
+	str, err := NewIntent("com.example/.DetailActivity").
+		Action("android.intent.action.VIEW").
+		Category("android.intent.category.DEFAULT").
+		Data("https://example.com/detail?id=1").
+		Flags(0x10000000).
+		String("title", "你好 a;b=c").
+		Int("id", 42).
+		Long("ts", 1584770463000).
+		Bool("vip", true).
+		Float("score", 4.5).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "intent://example.com/detail?id=1#Intent;scheme=https;action=android.intent.action.VIEW;" +
+		"category=android.intent.category.DEFAULT;launchFlags=0x10000000;component=com.example/.DetailActivity;" +
+		"S.title=%E4%BD%A0%E5%A5%BD%20a%3Bb%3Dc;i.id=42;l.ts=1584770463000;B.vip=true;f.score=4.5;end"
+	if str != want {
+		t.Fatalf("unexpected intent\n got: %s\nwant: %s", str, want)
+	}
+
+	intent, err := ParseIntent(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Intent{
+		Component:  "com.example/.DetailActivity",
+		Action:     "android.intent.action.VIEW",
+		Categories: []string{"android.intent.category.DEFAULT"},
+		Data:       "https://example.com/detail?id=1",
+		Flags:      0x10000000,
+		Extras: []IntentExtra{
+			{ExtraString, "title", "你好 a;b=c"},
+			{ExtraInt, "id", int32(42)},
+			{ExtraLong, "ts", int64(1584770463000)},
+			{ExtraBool, "vip", true},
+			{ExtraFloat, "score", float32(4.5)},
+		},
+	}
+	if !reflect.DeepEqual(intent, expected) {
+		t.Fatalf("unexpected intent %+v", intent)
+	}
+
+	// 生成的intent可以通过模板检查
+	if err = (TmplStartActivity{Intent: str}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseIntent_Standard(t *testing.T) {
+	// 个推文档中的示例
+	str := "intent:#Intent;launchFlags=0x04000000;action=android.intent.action.oppopush;package=com.getui.demo;" +
+		"component=com.getui.demo/com.getui.demo.TestActivity;S.parm1=value1;S.parm2=value2;end"
+	intent, err := ParseIntent(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Intent{
+		Component: "com.getui.demo/com.getui.demo.TestActivity",
+		Package:   "com.getui.demo",
+		Action:    "android.intent.action.oppopush",
+		Flags:     0x04000000,
+		Extras: []IntentExtra{
+			{ExtraString, "parm1", "value1"},
+			{ExtraString, "parm2", "value2"},
+		},
+	}
+	if !reflect.DeepEqual(intent, expected) {
+		t.Fatalf("unexpected intent %+v", intent)
+	}
+	if err = (TmplStartActivity{Intent: str}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	built, err := NewIntent(expected.Component).Package("com.getui.demo").Action(expected.Action).Flags(0x04000000).
+		String("parm1", "value1").String("parm2", "value2").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, _ := ParseIntent(built); !reflect.DeepEqual(parsed, expected) {
+		t.Fatalf("unexpected round trip %s", built)
+	}
+
+	// 其他标准字段忽略
+	str = "intent:#Intent;type=text/plain;sourceBounds=0%200%2010%2010;SEL;component=com.example/.MainActivity;end"
+	if intent, err = ParseIntent(str); err != nil || intent.Component != "com.example/.MainActivity" || len(intent.Extras) != 0 {
+		t.Fatalf("unexpected intent %+v %v", intent, err)
+	}
+}
+
+func TestIntent_Errors(t *testing.T) {
+	if _, err := NewIntent("com.example/.MainActivity").String("data", strings.Repeat("x", maxIntentLength)).Build(); err == nil {
+		t.Error("expected error for an intent over the length limit")
+	}
+	if _, err := NewIntent("com.example/.MainActivity").Int("id", 1<<40).Build(); err == nil {
+		t.Error("expected error for an int overflow")
+	}
+	if _, err := NewIntent("").Build(); err == nil {
+		t.Error("expected error for a missing component")
+	}
+
+	invalid := []string{
+		"#Intent;component=com.example/.MainActivity;end",
+		"intent:component=com.example/.MainActivity;end",
+		"intent:#Intent;component=com.example/.MainActivity;X.k=v;end",
+		"intent:#Intent;i.id=abc;end",
+		"intent:#Intent;S.key;end",
+	}
+	for _, str := range invalid {
+		if _, err := ParseIntent(str); err == nil {
+			t.Errorf("%s: expected error", str)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package GeTuiGo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// 接口类别，不同类别分别限流
+type EndpointFamily int
+
+const (
+	FamilySinglePush EndpointFamily = iota // 单推、批量单推
+	FamilyListPush                         // save_list_body、tolist群推
+	FamilyAppPush                          // 按条件群推
+	FamilyUser                             // 别名、标签、黑名单、用户状态等用户管理接口
+)
+
+// 各接口所属的类别，未列出的接口不限流
+var endpointFamilies = map[string]EndpointFamily{
+	"push_single":       FamilySinglePush,
+	"push_single_batch": FamilySinglePush,
+	"save_list_body":    FamilyListPush,
+	"push_list":         FamilyListPush,
+	"push_app":          FamilyAppPush,
+	"bind_alias":        FamilyUser,
+	"unbind_alias":      FamilyUser,
+	"unbind_alias_all":  FamilyUser,
+	"query_cid":         FamilyUser,
+	"query_alias":       FamilyUser,
+	"set_tags":          FamilyUser,
+	"get_tags":          FamilyUser,
+	"user_blk_list":     FamilyUser,
+	"user_status":       FamilyUser,
+
+	"push/single/cid":       FamilySinglePush,
+	"push/single/alias":     FamilySinglePush,
+	"push/single/batch/cid": FamilySinglePush,
+	"push/list/message":     FamilyListPush,
+	"push/list/cid":         FamilyListPush,
+	"push/all":              FamilyAppPush,
+	"push/tag":              FamilyAppPush,
+}
+
+// 超出本地每日推送配额，同时满足 errors.Is(err, ErrPushNumOverLimit)
+var ErrQuotaExceeded = fmt.Errorf("getui: daily push quota exceeded: %w", ErrPushNumOverLimit)
+
+// 令牌桶限流器，并发安全
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒生成的令牌数
+	burst  float64 // 令牌桶容量
+	tokens float64
+	last   time.Time
+}
+
+// 创建限流器
+//  rate	每秒允许的请求数
+//  burst	允许的突发请求数，小于1时按1处理
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// 取得一个令牌，没有可用令牌时等待，ctx结束时返回错误
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	tokens := l.tokens
+	l.mu.Unlock()
+
+	if tokens >= 0 {
+		return nil
+	}
+	if l.rate <= 0 {
+		l.cancel()
+		return errors.New("getui: rate limiter has zero rate")
+	}
+
+	wait := time.Duration(-tokens / l.rate * float64(time.Second))
+	if err := sleepContext(ctx, wait); err != nil {
+		l.cancel()
+		return err
+	}
+	return nil
+}
+
+// 归还未使用的令牌
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+	l.mu.Unlock()
+}
+
+// 每日推送配额统计，按北京时间自然日计数，并发安全
+//  可以在多个Client之间共享
+type QuotaTracker struct {
+	mu    sync.Mutex
+	limit int
+	day   string
+	used  int
+	now   func() time.Time
+}
+
+// 创建配额统计
+//  dailyLimit	每日最多推送数，达到后本地直接拒绝发送
+func NewQuotaTracker(dailyLimit int) *QuotaTracker {
+	return &QuotaTracker{
+		limit: dailyLimit,
+		now:   time.Now,
+	}
+}
+
+// 切换到新的一天时清零，调用前需持有锁
+func (q *QuotaTracker) rollLocked() {
+	day := q.now().In(Location).Format("2006-01-02")
+	if day != q.day {
+		q.day = day
+		q.used = 0
+	}
+}
+
+// 预占n次推送，超出配额时返回 ErrQuotaExceeded
+func (q *QuotaTracker) Reserve(n int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollLocked()
+	if q.used+n > q.limit {
+		return ErrQuotaExceeded
+	}
+	q.used += n
+	return nil
+}
+
+// 归还预占的推送次数，用于服务端拒绝推送的情况
+func (q *QuotaTracker) Release(n int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollLocked()
+	if q.used -= n; q.used < 0 {
+		q.used = 0
+	}
+}
+
+// 当天已使用的推送次数
+func (q *QuotaTracker) Used() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollLocked()
+	return q.used
+}
+
+// 当天剩余的推送次数
+func (q *QuotaTracker) Remaining() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollLocked()
+	return q.limit - q.used
+}
+
+// 为某一类接口设置限流
+//  rate	每秒允许的请求数
+//  burst	允许的突发请求数
+func WithRateLimit(family EndpointFamily, rate float64, burst int) Option {
+	return func(o *options) {
+		if o.limiters == nil {
+			o.limiters = make(map[EndpointFamily]*RateLimiter)
+		}
+		o.limiters[family] = NewRateLimiter(rate, burst)
+	}
+}
+
+// 设置每日推送配额统计，推送前先在本地检查配额
+func WithQuota(quota *QuotaTracker) Option {
+	return func(o *options) {
+		o.quota = quota
+	}
+}
+
+// 请求前按接口类别等待限流
+func (o *options) wait(ctx context.Context, endpoint string) error {
+	family, ok := endpointFamilies[endpoint]
+	if !ok {
+		return nil
+	}
+	if limiter := o.limiters[family]; limiter != nil {
+		return limiter.Wait(ctx)
+	}
+	return nil
+}
+
+// 推送前预占n次配额
+func (o *options) reserve(n int) error {
+	if o.quota == nil || n <= 0 {
+		return nil
+	}
+	return o.quota.Reserve(n)
+}
+
+// 推送完成后处理配额，服务端明确拒绝时归还预占的次数
+func (o *options) settle(n int, err error) {
+	var apiErr *APIError
+	if o.quota != nil && n > 0 && errors.As(err, &apiErr) {
+		o.quota.Release(n)
+	}
+}
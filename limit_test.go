@@ -0,0 +1,111 @@
+package GeTuiGo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 前2个令牌立即可用，后2个需要等待约20ms
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("limiter did not wait, elapsed %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	limiter = NewRateLimiter(0.1, 1)
+	limiter.Wait(ctx)
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestQuotaTracker(t *testing.T) {
+	now := time.Date(2020, 3, 21, 23, 0, 0, 0, Location)
+	quota := NewQuotaTracker(3)
+	quota.now = func() time.Time { return now }
+
+	if err := quota.Reserve(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := quota.Reserve(2); !errors.Is(err, ErrQuotaExceeded) || !errors.Is(err, ErrPushNumOverLimit) {
+		t.Fatalf("expected quota exceeded, got %v", err)
+	}
+
+	quota.Release(1)
+	if quota.Used() != 1 || quota.Remaining() != 2 {
+		t.Fatalf("unexpected used %d", quota.Used())
+	}
+
+	// 北京时间第二天清零
+	now = now.Add(2 * time.Hour)
+	if quota.Used() != 0 {
+		t.Fatalf("quota not reset on new day, used %d", quota.Used())
+	}
+}
+
+func TestClient_Quota(t *testing.T) {
+	pushes := 0
+	quota := NewQuotaTracker(2)
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		pushes++
+		if pushes == 1 {
+			w.Write([]byte(`{"result":"no_user"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"ok","taskid":"task"}`))
+	}, WithQuota(quota), WithRateLimit(FamilyListPush, 1000, 10))
+	defer server.Close()
+
+	// 服务端拒绝的推送不占用配额
+	if _, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"}); !errors.Is(err, ErrNoUser) {
+		t.Fatalf("expected ErrNoUser, got %v", err)
+	}
+	if quota.Used() != 0 {
+		t.Fatalf("unexpected used %d", quota.Used())
+	}
+
+	if _, err := client.PushList(&PushList{TaskId: "task", Cid: []string{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if pushes != 2 {
+		t.Fatalf("expected 2 requests to reach the server, got %d", pushes)
+	}
+}
+
+func TestClient_RateLimitRetry(t *testing.T) {
+	requests := 0
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryResults: []string{ResultTooFrequent}}
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"result":"too_frequent"}`))
+	}, WithRateLimit(FamilySinglePush, 20, 1), WithRetryPolicy(policy))
+	defer server.Close()
+
+	// 3次请求在20次每秒、容量1的限制下至少需要约100ms
+	start := time.Now()
+	if _, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"}); !errors.Is(err, ErrTooFrequent) {
+		t.Fatalf("expected ErrTooFrequent, got %v", err)
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("retries were not rate limited, elapsed %v", elapsed)
+	}
+}
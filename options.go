@@ -0,0 +1,91 @@
+package GeTuiGo
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 默认的v1接口地址
+const DefaultBaseURL = "https://restapi.getui.com/v1"
+
+// 客户端配置
+type options struct {
+	httpClient *http.Client                    // 发送请求使用的http客户端
+	baseURL    string                          // 接口地址，不包含appId部分
+	timeout    time.Duration                   // 单次请求超时时间，0表示不限制
+	userAgent  string                          // 请求头中的User-Agent
+	retry      RetryPolicy                     // 请求失败时的重试策略
+	limiters   map[EndpointFamily]*RateLimiter // 按接口类别限流
+	quota      *QuotaTracker                   // 每日推送配额统计
+	noValidate bool                            // 发送前不检查推送消息
+}
+
+// 客户端可选配置项，在NewClient时传入
+type Option func(*options)
+
+// 使用自定义的http客户端，可以设置代理、Transport等
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		if httpClient != nil {
+			o.httpClient = httpClient
+		}
+	}
+}
+
+// 设置接口地址，如 https://restapi.getui.com/v1，可用于指向本地测试服务
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// 设置单次请求超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// 设置请求头中的User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// 发送前不再自动检查推送消息，由调用方保证消息完整
+func WithoutValidation() Option {
+	return func(o *options) {
+		o.noValidate = true
+	}
+}
+
+// 按默认值和传入的配置项生成配置
+//  baseURL	未通过WithBaseURL设置时使用的接口地址
+func newOptions(baseURL string, opts []Option) options {
+	o := options{
+		httpClient: http.DefaultClient,
+		baseURL:    baseURL,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// 设置了超时时间时复制一份http客户端，避免修改调用方传入的对象
+	if o.timeout > 0 {
+		httpClient := *o.httpClient
+		httpClient.Timeout = o.timeout
+		o.httpClient = &httpClient
+	}
+	return o
+}
+
+// 发送请求，统一设置请求头
+func (o *options) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Content-Type", "application/json")
+	if o.userAgent != "" {
+		req.Header.Set("User-Agent", o.userAgent)
+	}
+	return o.httpClient.Do(req)
+}
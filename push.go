@@ -1,44 +1,41 @@
 package GeTuiGo
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type Client struct {
-	appId               string
-	appKey              string
-	masterSecret        string
-	authToken           string
-	authTokenExpireTime int
+	appId        string
+	appKey       string
+	masterSecret string
+	token        tokenCache // auth_token，过期前自动刷新
+	options
 }
 
 // 推送消息体
 type Push struct {
-	Message       *Message           // 消息内容
-	Notification  *TmplNotification  // 点开通知打开应用模板
-	Link          *TmplLink          // 点开通知打开网页模板
-	NotifyPopLoad *TmplNotifyPopLoad // 点击通知弹窗下载模板
-	StartActivity *TmplStartActivity // 点开通知打开应用内特定页面模板
-	Transmission  *TmplTransmission  // 透传消息模板
-	PushInfo      *ApnPushInfo       // apns推送消息, json串，当手机为ios，并且为离线的时候
-	Cid           string             // 与alias二选一
-	Alias         string             // 与cid二选一
-	RequestId     string             // 必传: 请求唯一标识
-	conditions    []Condition        // 筛选目标用户条件
-	speed         int                // 可选字段 推送速度控制
-	pushTime      time.Time          // 定时下发时间
-	taskName      string             // 可选字段 任务名称 可以给多个任务指定相同的task_name，后面用task_name查询推送结果能得到多个任务的结果
-	durationBegin time.Time          // 可选字段 设定展示开始时间，格式为yyyy-MM-dd HH:mm:ss
-	durationEnd   time.Time          // 可选字段 设定展示结束时间，格式为yyyy-MM-dd HH:mm:ss
+	Message       *Message     // 消息内容，MsgType由Template决定，为nil时使用默认设置
+	Template      INotify      // 消息模板，TmplXXX之一，推荐使用 NewPush 构造
+	PushInfo      *ApnPushInfo // apns推送消息, json串，当手机为ios，并且为离线的时候
+	Cid           string       // 与alias二选一
+	Alias         string       // 与cid二选一
+	RequestId     string       // 必传: 请求唯一标识
+	conditions    []Condition  // 筛选目标用户条件
+	speed         int          // 可选字段 推送速度控制
+	pushTime      time.Time    // 定时下发时间
+	taskName      string       // 可选字段 任务名称 可以给多个任务指定相同的task_name，后面用task_name查询推送结果能得到多个任务的结果
+	durationBegin time.Time    // 可选字段 设定展示开始时间，序列化为Asia/Shanghai时区的yyyy-MM-dd HH:mm:ss
+	durationEnd   time.Time    // 可选字段 设定展示结束时间，序列化为Asia/Shanghai时区的yyyy-MM-dd HH:mm:ss
 }
 
 type PushResult struct {
@@ -70,9 +67,10 @@ type Condition struct {
 	OptType int      `json:"opt_type"` // 必传: 筛选参数的组合，0:取参数并集or，1：交集and，2：相当与not in {参数1，参数2，....}
 }
 
-// 为推送消息添加筛选条件
-func (push *Push) AppendCondition(cond Condition) {
-	push.conditions = append(push.conditions, cond)
+// 为推送消息添加筛选条件，多个条件需要同时满足
+//  可以使用 Tag、Region、PhoneType 构造，或使用 ParseConditions 解析表达式
+func (push *Push) AppendCondition(conds ...Condition) {
+	push.conditions = append(push.conditions, conds...)
 }
 
 // 推送速度控制
@@ -80,7 +78,7 @@ func (push *Push) SetSpeed(speed int) {
 	push.speed = speed
 }
 
-// 设定展示开始时间
+// 设定展示时间段，不超过 MaxDisplayWindow
 //  begin 	开始时间
 //  end 	结束时间
 func (push *Push) SetDuration(begin, end time.Time) {
@@ -88,36 +86,38 @@ func (push *Push) SetDuration(begin, end time.Time) {
 	push.durationEnd = end
 }
 
-// 定时下发时间
+// 定时下发时间，需在当前时间 MinScheduleLead 之后、MaxScheduleLead 之内
 func (push *Push) SetPushTime(pushTime time.Time) {
 	push.pushTime = pushTime
 }
 
-// 转为json字符
-func (push *Push) ToJsonString(appKey string) string {
-	// 构造要发送的数据
-	push.Message.AppKey = appKey
+// 设置任务组名，用于 SinglePush、SaveListBody、PushToApp
+//  多个任务可以使用相同的组名，之后用 GetPushResultByGroup 或 GetGroupReport 查询整组的推送结果；
+//  最长100个字符，只能包含文字、数字、下划线和中划线
+func (push *Push) SetTaskName(name string) {
+	push.taskName = name
+}
+
+// 任务组名
+func (push *Push) TaskName() string {
+	return push.taskName
+}
+
+// 推送消息的各字段，与发送给个推接口的内容一致，不修改push
+func (push *Push) fields() map[string]interface{} {
+	// 消息应用类型由模板决定
+	message := Message{}
+	if push.Message != nil {
+		message = *push.Message
+	}
 
 	data := map[string]interface{}{
-		"message": push.Message,
-	}
-
-	switch push.Message.MsgType {
-	case "notification":
-		data["notification"] = push.Notification
-		break
-	case "link":
-		data["link"] = push.Link
-		break
-	case "notypopload":
-		data["notypopload"] = push.NotifyPopLoad
-		break
-	case "startactivity":
-		data["startactivity"] = push.StartActivity
-		break
-	case "transmission":
-		data["transmission"] = push.Transmission
-		break
+		"message": &message,
+	}
+
+	if push.Template != nil {
+		message.MsgType = push.Template.GetMsgType()
+		data[message.MsgType] = push.Template
 	}
 
 	if push.PushInfo != nil {
@@ -126,13 +126,12 @@ func (push *Push) ToJsonString(appKey string) string {
 
 	if push.Cid != "" {
 		data["cid"] = push.Cid
-	} else if push.Alias != "" {
+	}
+	if push.Alias != "" {
 		data["alias"] = push.Alias
 	}
 
-	// 请求唯一标识为空时，创建一个
-	if push.RequestId == "" {
-		push.RequestId = fmt.Sprintf("%d", time.Now().UnixNano())
+	if push.RequestId != "" {
 		data["requestid"] = push.RequestId
 	}
 
@@ -146,51 +145,143 @@ func (push *Push) ToJsonString(appKey string) string {
 		data["speed"] = push.speed
 	}
 
+	// 任务名称
+	if push.taskName != "" {
+		data["task_name"] = push.taskName
+	}
+
 	// 展示时间
 	if !push.durationBegin.IsZero() {
-		data["duration_begin"] = push.durationBegin.Format("2020-03-21 14:01:03")
+		data["duration_begin"] = formatTime(push.durationBegin)
 	}
 
 	if !push.durationEnd.IsZero() {
-		data["duration_end"] = push.durationEnd.Format("2020-03-21 14:01:03")
+		data["duration_end"] = formatTime(push.durationEnd)
 	}
 
 	// 定时下发时间
 	if !push.pushTime.IsZero() {
-		data["push_time"] = push.pushTime.Format("2020-03-21 14:01:03")
+		data["push_time"] = formatTime(push.pushTime)
+	}
+
+	return data
+}
+
+// 序列化推送消息，可以保存后用 UnmarshalJSON 还原，时间精确到秒
+//  格式与发送给个推接口的内容相同，不包含appkey，不会修改push
+func (push *Push) MarshalJSON() ([]byte, error) {
+	return json.Marshal(push.fields())
+}
+
+// 还原 MarshalJSON 或 ToJsonString 生成的推送消息
+func (push *Push) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Message       *Message     `json:"message"`
+		PushInfo      *ApnPushInfo `json:"push_info"`
+		Cid           string       `json:"cid"`
+		Alias         string       `json:"alias"`
+		RequestId     string       `json:"requestid"`
+		Conditions    []Condition  `json:"condition"`
+		Speed         int          `json:"speed"`
+		TaskName      string       `json:"task_name"`
+		DurationBegin string       `json:"duration_begin"`
+		DurationEnd   string       `json:"duration_end"`
+		PushTime      string       `json:"push_time"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p := Push{
+		Message:    aux.Message,
+		PushInfo:   aux.PushInfo,
+		Cid:        aux.Cid,
+		Alias:      aux.Alias,
+		RequestId:  aux.RequestId,
+		conditions: aux.Conditions,
+		speed:      aux.Speed,
+		taskName:   aux.TaskName,
+	}
+
+	// 按消息应用类型解析模板
+	if p.Message != nil && p.Message.MsgType != "" {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if tmplData, ok := raw[p.Message.MsgType]; ok {
+			tmpl, err := unmarshalTemplate(p.Message.MsgType, tmplData)
+			if err != nil {
+				return err
+			}
+			p.Template = tmpl
+		}
+	}
+
+	var err error
+	if p.durationBegin, err = parseTime(aux.DurationBegin); err != nil {
+		return err
+	}
+	if p.durationEnd, err = parseTime(aux.DurationEnd); err != nil {
+		return err
+	}
+	if p.pushTime, err = parseTime(aux.PushTime); err != nil {
+		return err
+	}
+
+	*push = p
+	return nil
+}
+
+// 转为发送给个推接口的json字符，不会修改push
+//  appKey	写入message.appkey
+//  RequestId为空时使用新生成的标识，需要重试时应先设置RequestId
+func (push *Push) ToJsonString(appKey string) string {
+	data := push.fields()
+	data["message"].(*Message).AppKey = appKey
+	if push.RequestId == "" {
+		data["requestid"] = newRequestId()
 	}
 
 	res, _ := json.Marshal(data)
 	return string(res)
 }
 
+// 请求唯一标识为空时，创建一个，重试时使用相同的标识
+func (push *Push) ensureRequestId() {
+	if push.RequestId == "" {
+		push.RequestId = newRequestId()
+	}
+}
+
+// 生成请求唯一标识
+func newRequestId() string {
+	return fmt.Sprintf("%d", time.Now().UnixNano())
+}
+
 type ListBody struct {
-	Message       Message           // 消息内容
-	Notification  TmplNotification  // 点开通知打开应用模板
-	Link          TmplLink          // 点开通知打开网页模板
-	NotifyPopLoad TmplNotifyPopLoad // 点击通知弹窗下载模板
-	StartActivity TmplStartActivity // 点开通知打开应用内特定页面模板
-	Transmission  TmplTransmission  // 透传消息模板
-	PushInfo      ApnPushInfo       // 	apns推送消息, json串，当手机为ios，并且为离线的时候
-	TaskName      string            // 任务名称,可以给多个任务指定相同的task_name，后面用task_name查询推送结果能得到多个任务的结果
+	Message  Message     // 消息内容
+	Template INotify     // 消息模板，TmplXXX之一
+	PushInfo ApnPushInfo // 	apns推送消息, json串，当手机为ios，并且为离线的时候
+	TaskName string      // 任务名称,可以给多个任务指定相同的task_name，后面用task_name查询推送结果能得到多个任务的结果
 }
 
 // 用户身份验证通过获得auth_token权限令牌，后面的请求都需要带上auth_token
-func (c *Client) getAutoToken(appId, appKey, masterSecret string) (authToken string, expTime int, err error) {
+func (c *Client) getAutoToken(ctx context.Context, appId, appKey, masterSecret string) (authToken string, expTime int, err error) {
 	timestamp := time.Now().UnixNano() / 1000000
 	sign := sha256.Sum256([]byte(fmt.Sprintf("%s%d%s", appKey, timestamp, masterSecret)))
 	data := fmt.Sprintf(`{"sign":"%x","timestamp":"%d","appkey":"%s"}`, sign, timestamp, appKey)
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/auth_sign", appId)
-	req, err := http.NewRequest("POST", url, strings.NewReader(data))
+	url := c.apiUrl("auth_sign")
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(data))
 	if err != nil {
 		return
 	}
 
-	req.Header.Add("Content-Type", "application/json")
-	response, err := http.DefaultClient.Do(req)
+	response, err := c.do(req)
 	if err != nil {
 		return
 	}
+	defer response.Body.Close()
 
 	respBody, err := ioutil.ReadAll(response.Body)
 	if err != nil {
@@ -204,12 +295,13 @@ func (c *Client) getAutoToken(appId, appKey, masterSecret string) (authToken str
 	}
 
 	err = json.Unmarshal(respBody, &respData)
-	if err != nil {
+	if err != nil && response.StatusCode/100 == 2 {
 		return
 	}
 
 	if respData.Result != ResultOk {
-		err = errors.New(respData.Result)
+		err = &APIError{Result: respData.Result, StatusCode: response.StatusCode, Endpoint: "auth_sign"}
+		return
 	}
 
 	expTime, _ = strconv.Atoi(respData.ExpireTime)
@@ -217,52 +309,173 @@ func (c *Client) getAutoToken(appId, appKey, masterSecret string) (authToken str
 	return
 }
 
-func NewClient(appId, appKey, masterSecret string) (*Client, error) {
+// 创建客户端并获取auth_token，auth_token过期前会自动刷新
+//  opts	可选配置，如 WithHTTPClient、WithBaseURL、WithTimeout、WithUserAgent
+func NewClient(appId, appKey, masterSecret string, opts ...Option) (*Client, error) {
+	return NewClientContext(context.Background(), appId, appKey, masterSecret, opts...)
+}
+
+// 同 NewClient，ctx结束时不再等待首次获取auth_token
+func NewClientContext(ctx context.Context, appId, appKey, masterSecret string, opts ...Option) (*Client, error) {
 	client := &Client{
 		appId:        appId,
 		appKey:       appKey,
 		masterSecret: masterSecret,
+		options:      newOptions(DefaultBaseURL, opts),
+	}
+	client.token.fetch = client.fetchToken
+	client.token.timeout = client.timeout
+
+	if _, err := client.token.get(ctx); err != nil {
+		return nil, err
 	}
+	return client, nil
+}
 
-	token, expTime, err := client.getAutoToken(appId, appKey, masterSecret)
+// 获取新的auth_token，expire_time为毫秒时间戳
+func (c *Client) fetchToken(ctx context.Context) (token string, expireAt time.Time, err error) {
+	token, expTime, err := c.getAutoToken(ctx, c.appId, c.appKey, c.masterSecret)
+	if err != nil {
+		return
+	}
+
+	if expTime > 0 {
+		expireAt = time.Unix(0, int64(expTime)*int64(time.Millisecond))
+	} else {
+		expireAt = time.Now().Add(tokenDefaultTTL)
+	}
+	return
+}
+
+// 拼接接口地址
+//  path	appId之后的路径，可以包含格式化占位符
+//  a	路径参数，如cid、别名、组名，逐个转义后填入path
+func (c *Client) apiUrl(path string, a ...interface{}) string {
+	args := make([]interface{}, len(a))
+	for i, arg := range a {
+		args[i] = url.PathEscape(fmt.Sprint(arg))
+	}
+	return fmt.Sprintf("%s/%s/", c.baseURL, c.appId) + fmt.Sprintf(path, args...)
+}
+
+// 带auth_token发送请求，失败时按重试策略重试
+//  响应结果不是ok或successed_xxx时返回 *APIError，respData仍会尽量解析
+func (c *Client) requestWithAuth(ctx context.Context, method, url, data string, respData interface{}) error {
+	respBody, err := c.request(ctx, c.endpoint(url), func() ([]byte, error) {
+		return c.attempt(ctx, method, url, data)
+	})
+	if respBody == nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(respBody, respData)
+	if err != nil {
+		return err
+	}
+	return decodeErr
+}
+
+// 发送一次请求，服务端返回not_auth时刷新auth_token后重发一次
+func (c *Client) attempt(ctx context.Context, method, url, data string) ([]byte, error) {
+	token, err := c.token.get(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	client.authToken = token
-	client.authTokenExpireTime = expTime
-	return client, nil
+	statusCode, respBody, err := c.send(ctx, method, url, data, token)
+	if err != nil {
+		return nil, err
+	}
+
+	apiErr := c.checkResponse(url, statusCode, respBody)
+	if apiErr != nil && apiErr.Result == ResultNotAuth {
+		if token, err = c.token.refresh(ctx, token); err != nil {
+			return nil, err
+		}
+		if statusCode, respBody, err = c.send(ctx, method, url, data, token); err != nil {
+			return nil, err
+		}
+		apiErr = c.checkResponse(url, statusCode, respBody)
+	}
+
+	if apiErr != nil {
+		return respBody, apiErr
+	}
+	return respBody, nil
 }
 
-func (c *Client) requestWithAuth(method, url, data string, respData interface{}) error {
+// 发送一次请求并读取响应内容
+func (c *Client) send(ctx context.Context, method, url, data, token string) (statusCode int, respBody []byte, err error) {
 	var reader io.Reader
 	if data != "" {
 		reader = strings.NewReader(data)
 	}
 
-	req, err := http.NewRequest(method, url, reader)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
-		return err
+		return
 	}
 
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("authtoken", c.authToken)
-	response, err := http.DefaultClient.Do(req)
+	req.Header.Add("authtoken", token)
+	response, err := c.do(req)
 	if err != nil {
-		return err
+		return
 	}
 	defer response.Body.Close()
 
-	respBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return err
+	respBody, err = ioutil.ReadAll(response.Body)
+	return response.StatusCode, respBody, err
+}
+
+// 检查响应结果，http状态码不是2xx或响应结果不是ok、successed_xxx时返回错误
+func (c *Client) checkResponse(url string, statusCode int, respBody []byte) *APIError {
+	var result struct {
+		Result string `json:"result"`
+		Desc   string `json:"desc"`
+	}
+	decodeErr := json.Unmarshal(respBody, &result)
+	if statusCode/100 == 2 && (decodeErr != nil || result.Result == "" || isSuccess(result.Result)) {
+		return nil
 	}
 
-	err = json.Unmarshal(respBody, &respData)
-	if err != nil {
-		return err
+	if decodeErr != nil {
+		result.Desc = strings.TrimSpace(string(respBody))
 	}
 
+	return &APIError{
+		Result:     result.Result,
+		Desc:       result.Desc,
+		StatusCode: statusCode,
+		Endpoint:   c.endpoint(url),
+	}
+}
+
+// 从接口地址中取出接口名称，如 push_single
+func (c *Client) endpoint(url string) string {
+	endpoint := strings.TrimPrefix(url, c.apiUrl(""))
+	if i := strings.IndexByte(endpoint, '/'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+	return endpoint
+}
+
+// 发送前检查推送消息，WithoutValidation 时不检查
+func (c *Client) validate(push *Push) error {
+	if c.noValidate {
+		return nil
+	}
+	return push.Validate()
+}
+
+// 单推前检查推送消息，另外要求cid和别名二选一
+func (c *Client) validateSingle(push *Push) error {
+	if err := c.validate(push); err != nil || c.noValidate {
+		return err
+	}
+	if push.Cid == "" && push.Alias == "" {
+		var v validator
+		v.add("cid", "cid or alias is required")
+		return v.err()
+	}
 	return nil
 }
 
@@ -278,25 +491,61 @@ func (c *Client) requestWithAuth(method, url, data string, respData interface{})
 //  - successed_online  在线下发
 //  - successed_ignore  非活跃用户不下发
 func (c *Client) SinglePush(push *Push) (result PushResult, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/push_single", c.appId)
+	return c.SinglePushContext(context.Background(), push)
+}
+
+// 同 SinglePush，ctx可用于取消请求或设置超时
+func (c *Client) SinglePushContext(ctx context.Context, push *Push) (result PushResult, err error) {
+	if err = c.validateSingle(push); err != nil {
+		return
+	}
+	push.ensureRequestId()
+	if err = c.reserve(1); err != nil {
+		return
+	}
+
+	url := c.apiUrl("push_single")
 	var respData PushResult
-	err = c.requestWithAuth("POST", url, push.ToJsonString(c.appKey), &respData)
-	return respData, err
+	err = c.requestWithAuth(ctx, "POST", url, push.ToJsonString(c.appKey), &respData)
+	c.settle(1, err)
+	return respData, withRequestId(err, push.RequestId)
 }
 
 type SinglePushBatchResult struct {
 	Result  string `json:"result"`
 	Details []struct {
-		TaskId string `json:"taskid"`
-		Cid    string `json:"cid"`
-		Status string `json:"status"`
+		TaskId    string `json:"taskid"`
+		Cid       string `json:"cid"`
+		RequestId string `json:"requestid"`
+		Status    string `json:"status"`
 	} `json:"details"`
 }
 
 // 批量单推接口
 //  在给每个用户的推送内容都不同的情况下，又因为单推消息发送较慢，可以使用此接口。
+//  每次最多200条消息，超过时使用 SinglePushBatchAll 分批发送
 func (c *Client) SinglePushBatch(pushList []*Push, needDetail bool) (result SinglePushBatchResult, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/push_single_batch", c.appId)
+	return c.SinglePushBatchContext(context.Background(), pushList, needDetail)
+}
+
+// 同 SinglePushBatch，ctx可用于取消请求或设置超时
+func (c *Client) SinglePushBatchContext(ctx context.Context, pushList []*Push, needDetail bool) (result SinglePushBatchResult, err error) {
+	if !c.noValidate && len(pushList) > maxSinglePushBatch {
+		var v validator
+		v.add("msg_list", "must not contain more than %d messages", maxSinglePushBatch)
+		return result, v.err()
+	}
+	for _, push := range pushList {
+		if err = c.validateSingle(push); err != nil {
+			return
+		}
+		push.ensureRequestId()
+	}
+	if err = c.reserve(len(pushList)); err != nil {
+		return
+	}
+
+	url := c.apiUrl("push_single_batch")
 
 	list := make([]string, len(pushList))
 	for i, push := range pushList {
@@ -304,12 +553,8 @@ func (c *Client) SinglePushBatch(pushList []*Push, needDetail bool) (result Sing
 		list[i] = str
 	}
 	body := fmt.Sprintf(`{"msg_list":[%s],"need_detail":%s}`, strings.Join(list, ","), strconv.FormatBool(needDetail))
-	err = c.requestWithAuth("POST", url, body, &result)
-
-	if err != nil {
-		return
-	}
-
+	err = c.requestWithAuth(ctx, "POST", url, body, &result)
+	c.settle(len(pushList), err)
 	return
 }
 
@@ -321,19 +566,25 @@ func (c *Client) SinglePushBatch(pushList []*Push, needDetail bool) (result Sing
 //  taskId  任务编号
 //  desc    错误信息描述
 func (c *Client) SaveListBody(push *Push) (result, taskId, desc string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/save_list_body", c.appId)
+	return c.SaveListBodyContext(context.Background(), push)
+}
+
+// 同 SaveListBody，ctx可用于取消请求或设置超时
+func (c *Client) SaveListBodyContext(ctx context.Context, push *Push) (result, taskId, desc string, err error) {
+	if err = c.validate(push); err != nil {
+		return
+	}
+	push.ensureRequestId()
+
+	url := c.apiUrl("save_list_body")
 	var respData struct {
 		Result string `json:"result"` // 响应结果，见详情
 		TaskId string `json:"taskid"` // 任务标识号，用于tolist接口的taskid
 		Desc   string `json:"desc"`   // 错误信息描述
 	}
 
-	err = c.requestWithAuth("POST", url, push.ToJsonString(c.appKey), &respData)
-	if err != nil {
-		return
-	}
-
-	return respData.Result, respData.TaskId, respData.Desc, nil
+	err = c.requestWithAuth(ctx, "POST", url, push.ToJsonString(c.appKey), &respData)
+	return respData.Result, respData.TaskId, respData.Desc, withRequestId(err, push.RequestId)
 }
 
 // 消息群发给cid list或者alias list列表对应的客户群，当两者并存的时候，以cid为准；并使用save_list_body返回的taskId，调用toList接口，完成群推推送。
@@ -341,60 +592,102 @@ func (c *Client) SaveListBody(push *Push) (result, taskId, desc string, err erro
 //
 //  result		推送结果
 func (c *Client) PushList(pushList *PushList) (result PushListResult, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/push_list", c.appId)
+	return c.PushListContext(context.Background(), pushList)
+}
+
+// 同 PushList，ctx可用于取消请求或设置超时
+func (c *Client) PushListContext(ctx context.Context, pushList *PushList) (result PushListResult, err error) {
+	if !c.noValidate {
+		if err = pushList.Validate(); err != nil {
+			return
+		}
+	}
+
+	// cid与alias并存时以cid为准
+	pushes := len(pushList.Cid)
+	if pushes == 0 {
+		pushes = len(pushList.Alias)
+	}
+	if err = c.reserve(pushes); err != nil {
+		return
+	}
+
+	url := c.apiUrl("push_list")
 	body, _ := json.Marshal(pushList)
-	err = c.requestWithAuth("POST", url, string(body), &result)
+	err = c.requestWithAuth(ctx, "POST", url, string(body), &result)
+	c.settle(pushes, err)
 	return
 }
 
 // 群推
 //  针对某个，根据筛选条件，将消息群发给符合条件客户群
 func (c *Client) PushToApp(push *Push) (result, taskId, desc string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/push_app", c.appId)
-	var resultData map[string]string
-	err = c.requestWithAuth("POST", url, push.ToJsonString(c.appKey), &resultData)
-	if err != nil {
+	return c.PushToAppContext(context.Background(), push)
+}
+
+// 同 PushToApp，ctx可用于取消请求或设置超时
+func (c *Client) PushToAppContext(ctx context.Context, push *Push) (result, taskId, desc string, err error) {
+	if err = c.validate(push); err != nil {
+		return
+	}
+	push.ensureRequestId()
+	if err = c.reserve(1); err != nil {
 		return
 	}
+
+	url := c.apiUrl("push_app")
+	var resultData map[string]string
+	err = c.requestWithAuth(ctx, "POST", url, push.ToJsonString(c.appKey), &resultData)
+	c.settle(1, err)
 	result = resultData["result"]
 	taskId = resultData["taskid"]
 	desc = resultData["desc"]
-	return
+	return result, taskId, desc, withRequestId(err, push.RequestId)
 }
 
 // stop群推任务
 //  在有效期内的消息进行停止
 func (c *Client) StopTask(taskId string) (result, respTaskId string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/stop_task/%s", c.appId, taskId)
+	return c.StopTaskContext(context.Background(), taskId)
+}
+
+// 同 StopTask，ctx可用于取消请求或设置超时
+func (c *Client) StopTaskContext(ctx context.Context, taskId string) (result, respTaskId string, err error) {
+	url := c.apiUrl("stop_task/%s", taskId)
 	var resultData map[string]string
-	err = c.requestWithAuth("DELETE", url, "", &resultData)
+	err = c.requestWithAuth(ctx, "DELETE", url, "", &resultData)
 	if err != nil {
 		return
 	}
 
 	result = resultData["result"]
-	taskId = resultData["taskid"]
+	respTaskId = resultData["taskid"]
 	return
 }
 
 type ScheduleTaskResult struct {
-	result     string
-	taskDetail struct {
-		pushContent string // 推送类容（transmission的内容）
-		pushTime    string // 推送时间
-		creatTime   string // 任务创建时间
-		sendResult  string // 任务状态
-	}
-	taskId string // 任务Id
+	Result     string `json:"result"`
+	TaskDetail struct {
+		PushContent interface{} `json:"pushContent"` // 推送类容（transmission的内容）
+		PushTime    string      `json:"pushTime"`    // 推送时间
+		CreatTime   string      `json:"creatTime"`   // 任务创建时间
+		SendResult  string      `json:"sendResult"`  // 任务状态
+	} `json:"taskDetail"`
+	TaskId string `json:"taskid"` // 任务Id
 }
 
 // 定时任务查询接口
 //  应用场景: 该接口主要用来在需要查看返回已提交的定时任务的相关信息。
 func (c *Client) GetScheduleTask(taskId string) (*ScheduleTaskResult, error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/get_schedule_task", c.appId)
+	return c.GetScheduleTaskContext(context.Background(), taskId)
+}
+
+// 同 GetScheduleTask，ctx可用于取消请求或设置超时
+func (c *Client) GetScheduleTaskContext(ctx context.Context, taskId string) (*ScheduleTaskResult, error) {
+	url := c.apiUrl("get_schedule_task")
 	var resultData = &ScheduleTaskResult{}
 
-	err := c.requestWithAuth("POST", url, fmt.Sprintf(`{"taskid":"%s"}`, taskId), &resultData)
+	err := c.requestWithAuth(ctx, "POST", url, fmt.Sprintf(`{"taskid":"%s"}`, taskId), &resultData)
 	if err != nil {
 		return nil, err
 	}
@@ -404,10 +697,15 @@ func (c *Client) GetScheduleTask(taskId string) (*ScheduleTaskResult, error) {
 // 定时任务删除接口
 //  应用场景: 用来删除还未下发的任务
 func (c *Client) DelScheduleTask(taskId string) (result string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/del_schedule_task", c.appId)
+	return c.DelScheduleTaskContext(context.Background(), taskId)
+}
+
+// 同 DelScheduleTask，ctx可用于取消请求或设置超时
+func (c *Client) DelScheduleTaskContext(ctx context.Context, taskId string) (result string, err error) {
+	url := c.apiUrl("del_schedule_task")
 	var resultData = map[string]string{}
-	err = c.requestWithAuth("POST", url, fmt.Sprintf(`{"taskid":"%s"}`, taskId), &resultData)
-	return resultData["result"], nil
+	err = c.requestWithAuth(ctx, "POST", url, fmt.Sprintf(`{"taskid":"%s"}`, taskId), &resultData)
+	return resultData["result"], err
 }
 
 type Alias struct {
@@ -421,13 +719,51 @@ type Alias struct {
 //  允许将多个ClientID和一个别名绑定，如用户使用多终端，则可将多终端对应的ClientID绑定为一个别名，
 //  目前一个别名最多支持绑定10个ClientID
 func (c *Client) BindAlias(aliasList []Alias) (result, desc string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/bind_alias", c.appId)
+	return c.BindAliasContext(context.Background(), aliasList)
+}
+
+// 同 BindAlias，ctx可用于取消请求或设置超时
+//  超过单次1000条的上限时分批顺序绑定，某批失败时停止并返回该批的 ChunkError
+func (c *Client) BindAliasContext(ctx context.Context, aliasList []Alias) (result, desc string, err error) {
+	if !c.noValidate {
+		var v validator
+		v.check(len(aliasList) > 0, "alias_list", "is required")
+		for i, item := range aliasList {
+			v.required(fmt.Sprintf("alias_list[%d].cid", i), item.Cid)
+			v.required(fmt.Sprintf("alias_list[%d].alias", i), item.Alias)
+		}
+		if err = v.err(); err != nil {
+			return
+		}
+	}
+
+	if len(aliasList) <= maxBindAlias {
+		return c.bindAlias(ctx, aliasList)
+	}
+	for start := 0; start < len(aliasList); start += maxBindAlias {
+		end := start + maxBindAlias
+		if end > len(aliasList) {
+			end = len(aliasList)
+		}
+		if result, desc, err = c.bindAlias(ctx, aliasList[start:end]); err != nil {
+			chunkErr := &ChunkError{Index: start / maxBindAlias, Err: err}
+			for _, item := range aliasList[start:end] {
+				chunkErr.Targets = append(chunkErr.Targets, item.Cid)
+			}
+			return result, desc, chunkErr
+		}
+	}
+	return
+}
+
+func (c *Client) bindAlias(ctx context.Context, aliasList []Alias) (result, desc string, err error) {
+	url := c.apiUrl("bind_alias")
 	var resultData = map[string]string{}
 
 	data, _ := json.Marshal(aliasList)
 	body := fmt.Sprintf(`{"alias_list":%s}`, data)
-	err = c.requestWithAuth("POST", url, body, &resultData)
-	return resultData["result"], resultData["desc"], nil
+	err = c.requestWithAuth(ctx, "POST", url, body, &resultData)
+	return resultData["result"], resultData["desc"], err
 }
 
 // 绑定别名
@@ -436,67 +772,91 @@ func (c *Client) BindAlias(aliasList []Alias) (result, desc string, err error) {
 //  允许将多个ClientID和一个别名绑定，如用户使用多终端，则可将多终端对应的ClientID绑定为一个别名，
 //  目前一个别名最多支持绑定10个ClientID
 func (c *Client) BindAlia(alias, cid string) (result, desc string, err error) {
-	data := make([]Alias, 1)
-	data = append(data, Alias{
-		Cid:   cid,
-		Alias: alias,
-	})
+	return c.BindAliaContext(context.Background(), alias, cid)
+}
 
-	return c.BindAlias(data)
+// 同 BindAlia，ctx可用于取消请求或设置超时
+func (c *Client) BindAliaContext(ctx context.Context, alias, cid string) (result, desc string, err error) {
+	return c.BindAliasContext(ctx, []Alias{{Cid: cid, Alias: alias}})
 }
 
 // 单个cid和别名解绑
 func (c *Client) UnBindAlias(cid, alias string) (result string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/unbind_alias", c.appId)
+	return c.UnBindAliasContext(context.Background(), cid, alias)
+}
 
-	data, err := json.Marshal(fmt.Sprintf(`{"cid":"%s","alias":"%s"}`, cid, alias))
+// 同 UnBindAlias，ctx可用于取消请求或设置超时
+func (c *Client) UnBindAliasContext(ctx context.Context, cid, alias string) (result string, err error) {
+	url := c.apiUrl("unbind_alias")
+
+	data, err := json.Marshal(Alias{Cid: cid, Alias: alias})
 	if err != nil {
 		return
 	}
 	var resultData = map[string]string{}
 
-	err = c.requestWithAuth("POST", url, string(data), &resultData)
-	return resultData["result"], nil
+	err = c.requestWithAuth(ctx, "POST", url, string(data), &resultData)
+	return resultData["result"], err
 }
 
 // 解绑别名所有cid
 func (c *Client) UnBindAliasAll(alias string) (result, desc string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/unbind_alias_all", c.appId)
+	return c.UnBindAliasAllContext(context.Background(), alias)
+}
+
+// 同 UnBindAliasAll，ctx可用于取消请求或设置超时
+func (c *Client) UnBindAliasAllContext(ctx context.Context, alias string) (result, desc string, err error) {
+	url := c.apiUrl("unbind_alias_all")
 	var resultData = map[string]string{}
 
-	data, err := json.Marshal(fmt.Sprintf(`{"alias":"%s"}`, alias))
+	data, err := json.Marshal(map[string]string{"alias": alias})
 	if err != nil {
 		return
 	}
 
-	err = c.requestWithAuth("POST", url, string(data), &resultData)
-	return resultData["result"], resultData["desc"], nil
+	err = c.requestWithAuth(ctx, "POST", url, string(data), &resultData)
+	return resultData["result"], resultData["desc"], err
 }
 
 // 查询别名cid
 //  通过传入的别名查询对应的cid信息
 func (c *Client) QueryCid(alias string) (result string, cidList []string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/query_cid/%s", c.appId, alias)
+	return c.QueryCidContext(context.Background(), alias)
+}
+
+// 同 QueryCid，ctx可用于取消请求或设置超时
+func (c *Client) QueryCidContext(ctx context.Context, alias string) (result string, cidList []string, err error) {
+	url := c.apiUrl("query_cid/%s", alias)
 	var resultData struct {
 		Result string   `json:"result"`
 		Cid    []string `json:"cid"`
 	}
-	err = c.requestWithAuth("GET", url, "", &resultData)
-	return resultData.Result, resultData.Cid, nil
+	err = c.requestWithAuth(ctx, "GET", url, "", &resultData)
+	return resultData.Result, resultData.Cid, err
 }
 
 // 查询cid别名
 //  通过传入的cid查询对应的别名
 func (c *Client) QueryAlias(cid string) (result string, alias string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/query_alias/%s", c.appId, cid)
+	return c.QueryAliasContext(context.Background(), cid)
+}
+
+// 同 QueryAlias，ctx可用于取消请求或设置超时
+func (c *Client) QueryAliasContext(ctx context.Context, cid string) (result string, alias string, err error) {
+	url := c.apiUrl("query_alias/%s", cid)
 	var resultData map[string]string
 
-	err = c.requestWithAuth("GET", url, "", &resultData)
-	return resultData["result"], resultData["alias"], nil
+	err = c.requestWithAuth(ctx, "GET", url, "", &resultData)
+	return resultData["result"], resultData["alias"], err
 }
 
 // 对指定用户设置tag属性
 func (c *Client) SetTags(cid string, tagList []string) (result string, err error) {
+	return c.SetTagsContext(context.Background(), cid, tagList)
+}
+
+// 同 SetTags，ctx可用于取消请求或设置超时
+func (c *Client) SetTagsContext(ctx context.Context, cid string, tagList []string) (result string, err error) {
 	data := struct {
 		Cid     string   `json:"cid"`
 		TagList []string `json:"tag_list"`
@@ -507,48 +867,71 @@ func (c *Client) SetTags(cid string, tagList []string) (result string, err error
 	var resultData map[string]string
 
 	body, err := json.Marshal(data)
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/set_tags", c.appKey)
+	if err != nil {
+		return
+	}
+	url := c.apiUrl("set_tags")
 
-	err = c.requestWithAuth("POST", url, string(body), &resultData)
-	return resultData["result"], nil
+	err = c.requestWithAuth(ctx, "POST", url, string(body), &resultData)
+	return resultData["result"], err
 }
 
 // 查询指定用户tag属性
 func (c *Client) GetTags(cid string) (result, tags string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/get_tags/%s", c.appKey, cid)
+	return c.GetTagsContext(context.Background(), cid)
+}
+
+// 同 GetTags，ctx可用于取消请求或设置超时
+func (c *Client) GetTagsContext(ctx context.Context, cid string) (result, tags string, err error) {
+	url := c.apiUrl("get_tags/%s", cid)
 	var resultData map[string]string
 
-	err = c.requestWithAuth("GET", url, "", &resultData)
-	return resultData["result"], resultData["cid"], nil
+	err = c.requestWithAuth(ctx, "GET", url, "", &resultData)
+	return resultData["result"], resultData["tags"], err
 }
 
 // 添加黑名单用户
 func (c *Client) AddBlackList(cidList []string) (result, desc string, err error) {
+	return c.AddBlackListContext(context.Background(), cidList)
+}
+
+// 同 AddBlackList，ctx可用于取消请求或设置超时
+func (c *Client) AddBlackListContext(ctx context.Context, cidList []string) (result, desc string, err error) {
 	data := fmt.Sprintf(`{"cid":["%s"]}`, strings.Join(cidList, `","`))
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/user_blk_list", c.appKey)
+	url := c.apiUrl("user_blk_list")
 	var resultData map[string]string
 
-	err = c.requestWithAuth("POST", url, data, &resultData)
-	return resultData["result"], resultData["desc"], nil
+	err = c.requestWithAuth(ctx, "POST", url, data, &resultData)
+	return resultData["result"], resultData["desc"], err
 }
 
 // 移除黑名单用户
 func (c *Client) RemoveBlackList(cidList []string) (result, desc string, err error) {
+	return c.RemoveBlackListContext(context.Background(), cidList)
+}
+
+// 同 RemoveBlackList，ctx可用于取消请求或设置超时
+func (c *Client) RemoveBlackListContext(ctx context.Context, cidList []string) (result, desc string, err error) {
 	data := fmt.Sprintf(`{"cid":["%s"]}`, strings.Join(cidList, `","`))
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/user_blk_list", c.appKey)
+	url := c.apiUrl("user_blk_list")
 	var resultData map[string]string
 
-	err = c.requestWithAuth("DELETE", url, data, &resultData)
-	return resultData["result"], resultData["desc"], nil
+	err = c.requestWithAuth(ctx, "DELETE", url, data, &resultData)
+	return resultData["result"], resultData["desc"], err
 }
 
 // 查询用户状态
 //  调用此接口可获取用户状态，如在线不在线
 func (c *Client) UserStatus(cid string) (result, lastLogin string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/user_status/%s", c.appKey, cid)
+	return c.UserStatusContext(context.Background(), cid)
+}
+
+// 同 UserStatus，ctx可用于取消请求或设置超时
+func (c *Client) UserStatusContext(ctx context.Context, cid string) (result, lastLogin string, err error) {
+	url := c.apiUrl("user_status/%s", cid)
 	var resultData map[string]string
-	err = c.requestWithAuth("GET", url, "", &resultData)
-	return resultData["result"], resultData["lastlogin"], nil
+	err = c.requestWithAuth(ctx, "GET", url, "", &resultData)
+	return resultData["result"], resultData["lastlogin"], err
 }
 
 // 查询数据对象
@@ -578,18 +961,23 @@ type PushResultDetail struct {
 // 获取推送结果接口
 //  调用此接口查询推送数据，可查询消息有效可下发总数，消息回执总数和用户点击数等结果。
 func (c *Client) GetPushResult(taskIdList []string) (result string, pushResultList []PushResultDetail, err error) {
+	return c.GetPushResultContext(context.Background(), taskIdList)
+}
+
+// 同 GetPushResult，ctx可用于取消请求或设置超时
+func (c *Client) GetPushResultContext(ctx context.Context, taskIdList []string) (result string, pushResultList []PushResultDetail, err error) {
 	if len(taskIdList) == 0 {
 		return
 	}
 	data := fmt.Sprintf(`{"taskIdList":["%s"]}`, strings.Join(taskIdList, `","`))
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/push_result", c.appKey)
+	url := c.apiUrl("push_result")
 	var resultData struct {
 		Result string             `json:"result"`
 		Data   []PushResultDetail `json:"data"`
 	}
 
-	err = c.requestWithAuth("POST", url, data, &resultData)
-	return resultData.Result, resultData.Data, nil
+	err = c.requestWithAuth(ctx, "POST", url, data, &resultData)
+	return resultData.Result, resultData.Data, err
 }
 
 type PushResultByGroup struct {
@@ -605,11 +993,23 @@ type PushResultByGroup struct {
 // 根据任务组名获取推送结果数据
 //  根据任务组名查询推送结果，返回结果包括百日内联网用户数（活跃用户数）、实际下发数、到达数、展示数、点击数。
 func (c *Client) GetPushResultByGroup(groupName string) (result PushResultByGroup, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/get_push_result_by_group_name/%s", c.appKey, groupName)
+	return c.GetPushResultByGroupContext(context.Background(), groupName)
+}
+
+// 同 GetPushResultByGroup，ctx可用于取消请求或设置超时
+func (c *Client) GetPushResultByGroupContext(ctx context.Context, groupName string) (result PushResultByGroup, err error) {
+	if !c.noValidate {
+		var v validator
+		v.taskName("group_name", groupName)
+		if err = v.err(); err != nil {
+			return
+		}
+	}
+	url := c.apiUrl("get_push_result_by_group_name/%s", groupName)
 	var resultData PushResultByGroup
 
-	err = c.requestWithAuth("POST", url, "", &resultData)
-	return resultData, nil
+	err = c.requestWithAuth(ctx, "POST", url, "", &resultData)
+	return resultData, err
 }
 
 type AppUserStat struct {
@@ -624,19 +1024,29 @@ type AppUserStat struct {
 // 获取单日用户数据接口
 //  调用此接口查询推送数据，可查询消息有效可下发总数，消息回执总数和用户点击数等结果。
 func (c *Client) QueryAppUser(date time.Time) (result string, stat AppUserStat, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/query_app_push/%s", c.appKey, date.Format("20200321"))
+	return c.QueryAppUserContext(context.Background(), date)
+}
+
+// 同 QueryAppUser，ctx可用于取消请求或设置超时
+func (c *Client) QueryAppUserContext(ctx context.Context, date time.Time) (result string, stat AppUserStat, err error) {
+	url := c.apiUrl("query_app_push/%s", date.In(Location).Format("20060102"))
 	var resultData struct {
 		Result string      `json:"result"`
 		Data   AppUserStat `json:"data"`
 	}
-	err = c.requestWithAuth("POST", url, "", &resultData)
-	return resultData.Result, resultData.Data, nil
+	err = c.requestWithAuth(ctx, "POST", url, "", &resultData)
+	return resultData.Result, resultData.Data, err
 }
 
 // 应用角标设置接口(仅iOS)
 //  badge	应用icon上显示的数字
 //  msgId	请求的msgid
 func (c *Client) IosSetBadge(badge int, msgId string, cidList, deviceTokenList []string) (result, desc string, err error) {
+	return c.IosSetBadgeContext(context.Background(), badge, msgId, cidList, deviceTokenList)
+}
+
+// 同 IosSetBadge，ctx可用于取消请求或设置超时
+func (c *Client) IosSetBadgeContext(ctx context.Context, badge int, msgId string, cidList, deviceTokenList []string) (result, desc string, err error) {
 	data := struct {
 		MsgId           string   `json:"msgid"`
 		Badge           int      `json:"badge"`
@@ -652,37 +1062,64 @@ func (c *Client) IosSetBadge(badge int, msgId string, cidList, deviceTokenList [
 	if err != nil {
 		return
 	}
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/set_badge", c.appKey)
+	url := c.apiUrl("set_badge")
 	var resultData map[string]string
 
-	err = c.requestWithAuth("POST", url, string(body), &resultData)
-	return resultData["result"], resultData["desc"], nil
+	err = c.requestWithAuth(ctx, "POST", url, string(body), &resultData)
+	return resultData["result"], resultData["desc"], err
 }
 
 // 按条件查询用户数
-//  通过指定查询条件来查询满足条件的用户数量
-func (c *Client) QueryUserCount(condition Condition) (result string, userCount int, err error) {
+//  通过指定查询条件来查询满足条件的用户数量，多个条件需要同时满足
+func (c *Client) QueryUserCount(conditions ...Condition) (result string, userCount int, err error) {
+	return c.QueryUserCountContext(context.Background(), conditions...)
+}
+
+// 同 QueryUserCount，ctx可用于取消请求或设置超时
+func (c *Client) QueryUserCountContext(ctx context.Context, conditions ...Condition) (result string, userCount int, err error) {
+	if !c.noValidate {
+		var v validator
+		v.check(len(conditions) > 0, "condition", "is required")
+		for i, cond := range conditions {
+			v.nested(fmt.Sprintf("condition[%d]", i), cond.Validate())
+		}
+		if err = v.err(); err != nil {
+			return
+		}
+	}
+
 	data := struct {
-		Condition Condition `json:"condition"`
-	}{Condition: condition}
+		Condition []Condition `json:"condition"`
+	}{Condition: conditions}
 
 	body, err := json.Marshal(data)
 	if err != nil {
 		return
 	}
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/query_user_count", c.appKey)
-	var resultData map[string]interface{}
+	url := c.apiUrl("query_user_count")
+	var resultData struct {
+		Result    string `json:"result"`
+		UserCount int    `json:"user_count"`
+	}
 
-	err = c.requestWithAuth("POST", url, string(body), &resultData)
-	return resultData["result"].(string), resultData["desc"].(int), nil
+	err = c.requestWithAuth(ctx, "POST", url, string(body), &resultData)
+	return resultData.Result, resultData.UserCount, err
 }
 
 // 获取可用bi标签
 //  查询应用可用的bi标签列表
 func (c *Client) QueryBiTags() (result string, tags []string, err error) {
-	url := fmt.Sprintf("https://restapi.getui.com/v1/%s/query_bi_tags", c.appKey)
-	var resultData map[string]interface{}
+	return c.QueryBiTagsContext(context.Background())
+}
+
+// 同 QueryBiTags，ctx可用于取消请求或设置超时
+func (c *Client) QueryBiTagsContext(ctx context.Context) (result string, tags []string, err error) {
+	url := c.apiUrl("query_bi_tags")
+	var resultData struct {
+		Result string   `json:"result"`
+		Tags   []string `json:"tags"`
+	}
 
-	err = c.requestWithAuth("POST", url, "", &resultData)
-	return resultData["result"].(string), resultData["tags"].([]string), nil
+	err = c.requestWithAuth(ctx, "POST", url, "", &resultData)
+	return resultData.Result, resultData.Tags, err
 }
@@ -1,12 +1,34 @@
 package GeTuiGo
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/litinghong/GeTuiGoClient/getuitest"
 )
 
+var fakeServer *getuitest.Server
+
+func TestMain(m *testing.M) {
+	fakeServer = getuitest.NewServer("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2")
+	fakeServer.AddUser("44b4da5e84150d87ea1509442d41e175", true)
+	code := m.Run()
+	fakeServer.Close()
+	os.Exit(code)
+}
+
 func getClient(t *testing.T) *Client {
-	client, err := NewClient("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2")
+	client, err := NewClient("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2",
+		WithBaseURL(fakeServer.BaseURL()))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -18,15 +40,107 @@ func TestNewClient(t *testing.T) {
 	getClient(t)
 }
 
+func TestNewClient_Options(t *testing.T) {
+	var path, userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		userAgent = r.UserAgent()
+		w.Write([]byte(`{"result":"ok","expire_time":"1585000000000","auth_token":"token"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("appId", "appKey", "secret",
+		WithBaseURL(server.URL+"/v1/"),
+		WithUserAgent("getui-test"),
+		WithTimeout(time.Second),
+		WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/v1/appId/auth_sign" {
+		t.Fatalf("unexpected path %s", path)
+	}
+	if userAgent != "getui-test" {
+		t.Fatalf("unexpected user agent %s", userAgent)
+	}
+	if client.httpClient.Timeout != time.Second || server.Client().Timeout != 0 {
+		t.Fatal("timeout not applied to a copy of the http client")
+	}
+	if client.token.token != "token" {
+		t.Fatalf("unexpected auth token %s", client.token.token)
+	}
+}
+
+func TestClient_SinglePushContext_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.SinglePushContext(ctx, &Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClient_FakeServerFailures(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	client, err := NewClient("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2",
+		WithBaseURL(fakeServer.BaseURL()), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeServer.ExpireTokens()
+	fakeServer.Fail("push_single", getuitest.Failure{StatusCode: http.StatusBadGateway, Times: 1})
+	fakeServer.Fail("push_single", getuitest.Failure{Result: ResultTooFrequent, Times: 1})
+	defer fakeServer.ClearFailures()
+
+	push := &Push{
+		Template: TmplTransmission{TransmissionContent: "FakeServerFailures"},
+		Cid:      "44b4da5e84150d87ea1509442d41e175",
+	}
+	before := len(fakeServer.MessagesTo(push.Cid))
+	result, err := client.SinglePush(push)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Status != ResultSuccessOnline || len(fakeServer.MessagesTo(push.Cid)) != before+1 {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	fakeServer.Fail("user_status", getuitest.Failure{Result: ResultOtherError})
+	if _, _, err := client.UserStatus(push.Cid); !errors.Is(err, ErrOtherError) {
+		t.Fatalf("expected ErrOtherError, got %v", err)
+	}
+}
+
+// 测试用的系统样式
+func newTestStyle(title string) StyleSystem {
+	style := NewStyleSystem()
+	style.Title = title
+	style.Text = "测试text"
+	style.Logo = "push.png"
+	return style
+}
+
 func TestClient_SinglePush(t *testing.T) {
 	client := getClient(t)
 
 	push := &Push{
-		Message: NewMessage(TypeNotification),
-		Notification: &TmplNotification{
+		Template: TmplNotification{
 			TransmissionType:    false,
 			TransmissionContent: "",
-			Style:               NewStyleSystem(),
+			Style:               newTestStyle("测试title"),
 		},
 		Cid: "44b4da5e84150d87ea1509442d41e175",
 	}
@@ -42,14 +156,13 @@ func TestClient_SinglePush(t *testing.T) {
 func TestClient_SinglePushBatch(t *testing.T) {
 	client := getClient(t)
 	pushList := make([]*Push, 0)
-	message := NewMessage(TypeNotification)
 	style := NewStyleSystem()
 	style.Title = "测试title"
 	style.Text = "测试test"
+	style.Logo = "push.png"
 
 	push := &Push{
-		Message: message,
-		Notification: &TmplNotification{
+		Template: TmplNotification{
 			TransmissionType:    false,
 			TransmissionContent: "",
 			Style:               style,
@@ -58,11 +171,10 @@ func TestClient_SinglePushBatch(t *testing.T) {
 	}
 
 	push2 := &Push{
-		Message: NewMessage(TypeNotification),
-		Notification: &TmplNotification{
+		Template: TmplNotification{
 			TransmissionType:    false,
 			TransmissionContent: "",
-			Style:               NewStyleSystem(),
+			Style:               newTestStyle("测试title"),
 		},
 		Cid: "xx",
 	}
@@ -78,10 +190,10 @@ func TestClient_SaveListBody(t *testing.T) {
 	style := NewStyleSystem()
 	style.Title = "测试title"
 	style.Text = "测试test"
+	style.Logo = "push.png"
 
 	push := &Push{
-		Message: NewMessage(TypeNotification),
-		Notification: &TmplNotification{
+		Template: TmplNotification{
 			TransmissionType:    false,
 			TransmissionContent: "",
 			Style:               style,
@@ -116,10 +228,10 @@ func TestClient_PushToApp(t *testing.T) {
 	style := NewStyleSystem()
 	style.Title = "PushToApp 测试title"
 	style.Text = "PushToApp 测试test"
+	style.Logo = "push.png"
 
 	push := &Push{
-		Message: NewMessage(TypeNotification),
-		Notification: &TmplNotification{
+		Template: TmplNotification{
 			TransmissionType:    false,
 			TransmissionContent: "",
 			Style:               style,
@@ -136,7 +248,16 @@ func TestClient_PushToApp(t *testing.T) {
 
 func TestClient_StopTask(t *testing.T) {
 	client := getClient(t)
-	result, respTaskId, err := client.StopTask("asd")
+
+	push := &Push{
+		Template: TmplTransmission{TransmissionContent: "StopTask"},
+	}
+	_, taskId, _, err := client.PushToApp(push)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, respTaskId, err := client.StopTask(taskId)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -144,10 +265,24 @@ func TestClient_StopTask(t *testing.T) {
 	t.Log(result, respTaskId)
 }
 
+// 创建定时群推任务
+func scheduleTask(t *testing.T, client *Client) string {
+	push := &Push{
+		Template: TmplTransmission{TransmissionContent: "ScheduleTask"},
+	}
+	push.SetPushTime(time.Now().Add(time.Hour))
+
+	_, taskId, _, err := client.PushToApp(push)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return taskId
+}
+
 func TestClient_GetScheduleTask(t *testing.T) {
 	client := getClient(t)
 
-	result, err := client.GetScheduleTask("task123")
+	result, err := client.GetScheduleTask(scheduleTask(t, client))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -158,7 +293,7 @@ func TestClient_GetScheduleTask(t *testing.T) {
 func TestClient_DelScheduleTask(t *testing.T) {
 	client := getClient(t)
 
-	result, err := client.DelScheduleTask("test123")
+	result, err := client.DelScheduleTask(scheduleTask(t, client))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -224,11 +359,10 @@ func TestClient_GetPushResult(t *testing.T) {
 	client := getClient(t)
 
 	push := &Push{
-		Message: NewMessage(TypeNotification),
-		Notification: &TmplNotification{
+		Template: TmplNotification{
 			TransmissionType:    false,
 			TransmissionContent: "",
-			Style:               NewStyleSystem(),
+			Style:               newTestStyle("测试title"),
 		},
 		Cid: "44b4da5e84150d87ea1509442d41e175",
 	}
@@ -244,3 +378,72 @@ func TestClient_GetPushResult(t *testing.T) {
 	}
 	t.Log(result2, pushResultDetail)
 }
+
+func TestPush_JSONRoundTrip(t *testing.T) {
+	begin := time.Now().Add(time.Hour).Truncate(time.Second)
+	push, err := NewPush().
+		ToAlias("alias").
+		Link(TmplLink{Url: "http://example.com", Style: newTestStyle("JSON"), DurationBegin: begin, DurationEnd: begin.Add(time.Hour)}).
+		Offline(time.Hour).
+		RequestId("request-1").
+		ApnPushInfo(&ApnPushInfo{Payload: "payload"}).
+		Condition(Condition{Key: "tag", Values: []string{"vip"}}).
+		Speed(100).
+		PushTime(begin).
+		Duration(begin, begin.Add(time.Hour)).
+		TaskName("task").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := *push
+	data, err := json.Marshal(push)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, *push) {
+		t.Fatal("MarshalJSON should not modify push")
+	}
+
+	var decoded Push
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := json.Marshal(&decoded)
+	if string(again) != string(data) {
+		t.Fatalf("round trip mismatch\n got: %s\nwant: %s", again, data)
+	}
+	if !decoded.pushTime.Equal(push.pushTime) || decoded.taskName != push.taskName || decoded.speed != push.speed {
+		t.Fatalf("unexported fields were not restored: %+v", decoded)
+	}
+	if _, ok := decoded.Template.(TmplLink); !ok {
+		t.Fatalf("expected TmplLink, got %T", decoded.Template)
+	}
+
+	// ToJsonString同样不修改push
+	noId := &Push{Template: TmplTransmission{TransmissionContent: "ToJsonString"}}
+	noId.ToJsonString("appKey")
+	if noId.RequestId != "" || noId.Message != nil {
+		t.Fatalf("ToJsonString should not modify push: %+v", noId)
+	}
+}
+
+func TestClient_apiUrlEscape(t *testing.T) {
+	client := getClient(t)
+
+	if u := client.apiUrl("query_cid/%s", "a/b?c%d"); !strings.HasSuffix(u, "/query_cid/a%2Fb%3Fc%25d") {
+		t.Fatalf("path arguments should be escaped, got %s", u)
+	}
+
+	alias := "user/1?x=%41 #"
+	if _, _, err := client.BindAlia(alias, "escape-cid"); err != nil {
+		t.Fatal(err)
+	}
+	if _, cids, err := client.QueryCid(alias); err != nil || len(cids) != 1 || cids[0] != "escape-cid" {
+		t.Fatalf("unexpected cids %v %v", cids, err)
+	}
+	if _, got, err := client.QueryAlias("escape-cid"); err != nil || got != alias {
+		t.Fatalf("unexpected alias %q %v", got, err)
+	}
+}
@@ -0,0 +1,526 @@
+package GeTuiGo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 默认的v2接口地址
+const DefaultBaseURLV2 = "https://restapi.getui.com/v2"
+
+// v2接口错误码
+const (
+	CodeV2Success         = 0     // 成功
+	CodeV2TokenInvalid    = 10001 // token错误或失效
+	CodeV2BlackList       = 10002 // appId或ip在黑名单中
+	CodeV2AuthTooFrequent = 10003 // 每分钟鉴权频率超限
+	CodeV2TooFrequent     = 10005 // 每分钟调用频率超限
+	CodeV2InvalidParam    = 20001 // 请求参数错误
+)
+
+// 通知点击后的动作
+const (
+	ClickTypeIntent        = "intent"         // 打开应用内特定页面
+	ClickTypeUrl           = "url"            // 打开网页地址
+	ClickTypePayload       = "payload"        // 自定义消息内容启动应用
+	ClickTypePayloadCustom = "payload_custom" // 自定义消息内容不启动应用
+	ClickTypeStartApp      = "startapp"       // 打开应用首页
+	ClickTypeNone          = "none"           // 纯通知，无后续动作
+)
+
+// v2推送条件
+type SettingsV2 struct {
+	TTL          int64          `json:"ttl,omitempty"`           // 消息离线时间，单位毫秒，-1表示不设离线
+	Strategy     map[string]int `json:"strategy,omitempty"`      // 厂商通道策略，如 {"default":1}
+	Speed        int            `json:"speed,omitempty"`         // 定速推送，每秒推送数
+	ScheduleTime int64          `json:"schedule_time,omitempty"` // 定时推送时间，毫秒时间戳
+}
+
+// v2推送目标用户
+type AudienceV2 struct {
+	Cid   []string `json:"cid,omitempty"`   // cid数组
+	Alias []string `json:"alias,omitempty"` // 别名数组
+	Tag   []TagV2  `json:"tag,omitempty"`   // 推送条件
+}
+
+func (a *AudienceV2) cids() []string {
+	if a == nil {
+		return nil
+	}
+	return a.Cid
+}
+
+func (a *AudienceV2) aliases() []string {
+	if a == nil {
+		return nil
+	}
+	return a.Alias
+}
+
+// v2推送条件
+type TagV2 struct {
+	Key     string   `json:"key"`      // 必传: 查询条件，如 phone_type、region、custom_tag
+	Values  []string `json:"values"`   // 必传: 查询条件值列表
+	OptType string   `json:"opt_type"` // 必传: or(或)、and(与)、not(非)
+}
+
+// v2个推通道消息内容，notification、transmission、revoke三选一
+type PushMessageV2 struct {
+	Duration     string          `json:"duration,omitempty"`     // 展示时间段，格式为毫秒时间戳"开始-结束"
+	Notification *NotificationV2 `json:"notification,omitempty"` // 通知消息内容，仅支持安卓系统
+	Transmission string          `json:"transmission,omitempty"` // 透传消息内容
+	Revoke       *RevokeV2       `json:"revoke,omitempty"`       // 撤回消息
+}
+
+// v2通知消息内容
+type NotificationV2 struct {
+	Title        string `json:"title"`                   // 必传: 通知标题
+	Body         string `json:"body"`                    // 必传: 通知内容
+	BigText      string `json:"big_text,omitempty"`      // 长文本消息内容
+	BigImage     string `json:"big_image,omitempty"`     // 大图的URL地址
+	Logo         string `json:"logo,omitempty"`          // 通知的图标名称，包含后缀名
+	LogoUrl      string `json:"logo_url,omitempty"`      // 通知图标URL地址
+	ChannelId    string `json:"channel_id,omitempty"`    // 通知渠道id
+	ChannelName  string `json:"channel_name,omitempty"`  // 通知渠道名称
+	ChannelLevel int    `json:"channel_level,omitempty"` // 通知渠道重要性，0~4
+	ClickType    string `json:"click_type"`              // 必传: 点击通知后续动作，使用 ClickTypeXXX 常量
+	Intent       string `json:"intent,omitempty"`        // click_type为intent时必传
+	Url          string `json:"url,omitempty"`           // click_type为url时必传
+	Payload      string `json:"payload,omitempty"`       // click_type为payload、payload_custom时必传
+	NotifyId     int    `json:"notify_id,omitempty"`     // 覆盖任务时使用相同的notifyId
+	RingName     string `json:"ring_name,omitempty"`     // 自定义铃声
+	BadgeAddNum  int    `json:"badge_add_num,omitempty"` // 角标增加数
+	ThreadId     string `json:"thread_id,omitempty"`     // 消息折叠分组
+}
+
+// v2撤回消息
+type RevokeV2 struct {
+	OldTaskId string `json:"old_task_id"`     // 必传: 需要撤回的taskId
+	Force     bool   `json:"force,omitempty"` // 在没有找到对应的taskId时是否上报异常
+}
+
+// v2厂商通道消息内容
+type PushChannelV2 struct {
+	Ios     *IosChannelV2     `json:"ios,omitempty"`
+	Android *AndroidChannelV2 `json:"android,omitempty"`
+	Harmony *HarmonyChannelV2 `json:"harmony,omitempty"` // 可以使用 SetVendor 设置
+}
+
+// v2 iOS通道消息内容
+type IosChannelV2 struct {
+	Type           string `json:"type,omitempty"`             // notify：apns通知消息，voip：voip语音推送
+	Aps            *ApsV2 `json:"aps,omitempty"`              // 推送通知消息内容
+	AutoBadge      string `json:"auto_badge,omitempty"`       // 角标，如"+1"
+	Payload        string `json:"payload,omitempty"`          // 增加自定义的数据
+	ApnsCollapseId string `json:"apns-collapse-id,omitempty"` // 相同的id会合并展示
+}
+
+// v2 apns推送通知消息内容
+type ApsV2 struct {
+	Alert            *ApsAlertV2 `json:"alert,omitempty"`
+	ContentAvailable int         `json:"content-available"` // 0表示普通通知消息，1表示静默推送
+	Sound            string      `json:"sound,omitempty"`
+	Category         string      `json:"category,omitempty"`
+	ThreadId         string      `json:"thread-id,omitempty"`
+}
+
+// v2 apns通知内容
+type ApsAlertV2 struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// v2 安卓厂商通道消息内容
+//  使用 PushChannelV2.SetVendor 可以按厂商设置类型化的参数
+type AndroidChannelV2 struct {
+	Ups *UpsV2 `json:"ups,omitempty"`
+}
+
+// v2 安卓厂商通道消息，notification、transmission二选一
+type UpsV2 struct {
+	Notification *UpsNotificationV2                `json:"notification,omitempty"`
+	Transmission string                            `json:"transmission,omitempty"`
+	Options      map[string]map[string]interface{} `json:"options,omitempty"` // 各厂商的扩展参数
+}
+
+// v2 安卓厂商通道通知内容
+type UpsNotificationV2 struct {
+	Title     string `json:"title"`               // 必传: 通知标题
+	Body      string `json:"body"`                // 必传: 通知内容
+	ClickType string `json:"click_type"`          // 必传: 使用 ClickTypeXXX 常量
+	Intent    string `json:"intent,omitempty"`    // click_type为intent时必传
+	Url       string `json:"url,omitempty"`       // click_type为url时必传
+	NotifyId  int    `json:"notify_id,omitempty"` // 覆盖任务时使用相同的notifyId
+}
+
+// v2 鸿蒙通道消息内容，notification、transmission二选一
+type HarmonyChannelV2 struct {
+	Notification *HarmonyNotificationV2 `json:"notification,omitempty"`
+	Transmission string                 `json:"transmission,omitempty"`
+}
+
+// v2 鸿蒙通道通知内容
+type HarmonyNotificationV2 struct {
+	Title       string `json:"title"`                   // 必传: 通知标题
+	Body        string `json:"body"`                    // 必传: 通知内容
+	Category    string `json:"category"`                // 必传: 通知消息分类
+	ClickType   string `json:"click_type"`              // 必传: want或startapp
+	Want        string `json:"want,omitempty"`          // click_type为want时必传
+	NotifyId    int    `json:"notify_id,omitempty"`     // 覆盖任务时使用相同的notifyId
+	BadgeAddNum int    `json:"badge_add_num,omitempty"` // 角标增加数
+}
+
+// v2推送消息体
+type PushRequestV2 struct {
+	RequestId   string         `json:"request_id"`             // 必传: 请求唯一标识，为空时发送时自动生成，不修改请求
+	GroupName   string         `json:"group_name,omitempty"`   // 任务组名
+	Settings    *SettingsV2    `json:"settings,omitempty"`     // 推送条件设置
+	Audience    *AudienceV2    `json:"audience,omitempty"`     // 推送目标用户
+	PushMessage *PushMessageV2 `json:"push_message"`           // 必传: 个推通道消息内容
+	PushChannel *PushChannelV2 `json:"push_channel,omitempty"` // 厂商通道消息内容
+}
+
+// 返回要发送的请求，请求唯一标识为空时复制一份并生成标识，重试时使用相同的标识
+func (req *PushRequestV2) outgoing() *PushRequestV2 {
+	if req.RequestId != "" {
+		return req
+	}
+	r := *req
+	r.RequestId = newRequestId()
+	return &r
+}
+
+// v2推送结果，taskid -> cid或别名 -> 推送状态
+type PushResultV2 map[string]map[string]string
+
+// 个推v2接口客户端
+type ClientV2 struct {
+	appId        string
+	appKey       string
+	masterSecret string
+	token        tokenCache
+	options
+}
+
+// 创建v2客户端并获取token，token过期前会自动刷新
+//  opts	可选配置，与 NewClient 相同，WithBaseURL 需要设置为v2接口地址
+func NewClientV2(appId, appKey, masterSecret string, opts ...Option) (*ClientV2, error) {
+	return NewClientV2Context(context.Background(), appId, appKey, masterSecret, opts...)
+}
+
+// 同 NewClientV2，ctx结束时不再等待首次获取token
+func NewClientV2Context(ctx context.Context, appId, appKey, masterSecret string, opts ...Option) (*ClientV2, error) {
+	client := &ClientV2{
+		appId:        appId,
+		appKey:       appKey,
+		masterSecret: masterSecret,
+		options:      newOptions(DefaultBaseURLV2, opts),
+	}
+	client.token.fetch = client.fetchToken
+	client.token.timeout = client.timeout
+
+	if _, err := client.token.get(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// v2响应结构
+type responseV2 struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// 鉴权，获取新的token
+func (c *ClientV2) fetchToken(ctx context.Context) (token string, expireAt time.Time, err error) {
+	timestamp := time.Now().UnixNano() / 1000000
+	sign := sha256.Sum256([]byte(fmt.Sprintf("%s%d%s", c.appKey, timestamp, c.masterSecret)))
+	data := fmt.Sprintf(`{"sign":"%x","timestamp":"%d","appkey":"%s"}`, sign, timestamp, c.appKey)
+
+	statusCode, respBody, err := c.send(ctx, "POST", "auth", data, "")
+	if err != nil {
+		return
+	}
+
+	var respData struct {
+		ExpireTime string `json:"expire_time"`
+		Token      string `json:"token"`
+	}
+	if err = c.decode("auth", statusCode, respBody, &respData); err != nil {
+		return
+	}
+
+	token = respData.Token
+	if expTime, _ := strconv.ParseInt(respData.ExpireTime, 10, 64); expTime > 0 {
+		expireAt = time.Unix(0, expTime*int64(time.Millisecond))
+	} else {
+		expireAt = time.Now().Add(tokenDefaultTTL)
+	}
+	return
+}
+
+// 发送一次请求并读取响应内容
+//  endpoint	appId之后的路径，如 push/single/cid
+func (c *ClientV2) send(ctx context.Context, method, endpoint, data, token string) (statusCode int, respBody []byte, err error) {
+	var reader io.Reader
+	if data != "" {
+		reader = strings.NewReader(data)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", c.baseURL, c.appId, endpoint)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return
+	}
+
+	if token != "" {
+		req.Header.Add("token", token)
+	}
+	response, err := c.do(req)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	respBody, err = ioutil.ReadAll(response.Body)
+	return response.StatusCode, respBody, err
+}
+
+// 检查响应结果并解析data字段，code不为0或http状态码不是2xx时返回 *APIError
+func (c *ClientV2) decode(endpoint string, statusCode int, respBody []byte, respData interface{}) error {
+	var resp responseV2
+	decodeErr := json.Unmarshal(respBody, &resp)
+	if statusCode/100 == 2 && decodeErr == nil && resp.Code == CodeV2Success {
+		if respData == nil || len(resp.Data) == 0 {
+			return nil
+		}
+		return json.Unmarshal(resp.Data, respData)
+	}
+	if statusCode/100 == 2 && decodeErr != nil {
+		return decodeErr
+	}
+
+	apiErr := &APIError{
+		Code:       resp.Code,
+		Desc:       resp.Msg,
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+	}
+	if decodeErr != nil {
+		apiErr.Desc = strings.TrimSpace(string(respBody))
+	}
+	return apiErr
+}
+
+// 带token发送请求，失败时按重试策略重试，token失效时刷新后重发一次
+func (c *ClientV2) requestWithAuth(ctx context.Context, method, endpoint, data string, respData interface{}) error {
+	_, err := c.request(ctx, endpoint, func() ([]byte, error) {
+		token, err := c.token.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		statusCode, respBody, err := c.send(ctx, method, endpoint, data, token)
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.decode(endpoint, statusCode, respBody, respData)
+		if apiErr, ok := err.(*APIError); ok && apiErr.Code == CodeV2TokenInvalid {
+			if token, err = c.token.refresh(ctx, token); err != nil {
+				return nil, err
+			}
+			if statusCode, respBody, err = c.send(ctx, method, endpoint, data, token); err != nil {
+				return nil, err
+			}
+			err = c.decode(endpoint, statusCode, respBody, respData)
+		}
+		return respBody, err
+	})
+	return err
+}
+
+// 推送请求，处理配额、请求唯一标识
+//  pushes	本次推送占用的配额数
+func (c *ClientV2) push(ctx context.Context, endpoint string, req *PushRequestV2, pushes int, respData interface{}) error {
+	req = req.outgoing()
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	if err := c.reserve(pushes); err != nil {
+		return err
+	}
+
+	err = c.requestWithAuth(ctx, "POST", endpoint, string(body), respData)
+	c.settle(pushes, err)
+	return withRequestId(err, req.RequestId)
+}
+
+// 执行cid单推
+//  req.Audience.Cid 只能包含一个cid
+func (c *ClientV2) SinglePushCid(req *PushRequestV2) (PushResultV2, error) {
+	return c.SinglePushCidContext(context.Background(), req)
+}
+
+// 同 SinglePushCid，ctx可用于取消请求或设置超时
+func (c *ClientV2) SinglePushCidContext(ctx context.Context, req *PushRequestV2) (result PushResultV2, err error) {
+	if !c.noValidate {
+		if err = validateSingleV2("cid", req.Audience.cids()); err != nil {
+			return
+		}
+	}
+	err = c.push(ctx, "push/single/cid", req, 1, &result)
+	return
+}
+
+// 执行别名单推
+//  req.Audience.Alias 只能包含一个别名
+func (c *ClientV2) SinglePushAlias(req *PushRequestV2) (PushResultV2, error) {
+	return c.SinglePushAliasContext(context.Background(), req)
+}
+
+// 同 SinglePushAlias，ctx可用于取消请求或设置超时
+func (c *ClientV2) SinglePushAliasContext(ctx context.Context, req *PushRequestV2) (result PushResultV2, err error) {
+	if !c.noValidate {
+		if err = validateSingleV2("alias", req.Audience.aliases()); err != nil {
+			return
+		}
+	}
+	err = c.push(ctx, "push/single/alias", req, 1, &result)
+	return
+}
+
+// 批量发送单推消息，每个cid用户的推送内容都不同
+//  reqList	每条消息的 Audience.Cid 只能包含一个cid，每次最多200条
+//  isAsync	是否异步推送，异步推送不返回推送结果
+func (c *ClientV2) SinglePushBatchCid(reqList []*PushRequestV2, isAsync bool) (PushResultV2, error) {
+	return c.SinglePushBatchCidContext(context.Background(), reqList, isAsync)
+}
+
+// 同 SinglePushBatchCid，ctx可用于取消请求或设置超时
+func (c *ClientV2) SinglePushBatchCidContext(ctx context.Context, reqList []*PushRequestV2, isAsync bool) (result PushResultV2, err error) {
+	if !c.noValidate {
+		if err = validateSinglePushBatchV2(reqList); err != nil {
+			return
+		}
+	}
+	msgList := make([]*PushRequestV2, len(reqList))
+	for i, req := range reqList {
+		msgList[i] = req.outgoing()
+	}
+	body, err := json.Marshal(struct {
+		IsAsync bool             `json:"is_async"`
+		MsgList []*PushRequestV2 `json:"msg_list"`
+	}{isAsync, msgList})
+	if err != nil {
+		return
+	}
+
+	if err = c.reserve(len(reqList)); err != nil {
+		return
+	}
+
+	err = c.requestWithAuth(ctx, "POST", "push/single/batch/cid", string(body), &result)
+	c.settle(len(reqList), err)
+	return
+}
+
+// 创建消息，用于tolist群推，返回的taskId传给 PushListCid
+func (c *ClientV2) CreateListMessage(req *PushRequestV2) (taskId string, err error) {
+	return c.CreateListMessageContext(context.Background(), req)
+}
+
+// 同 CreateListMessage，ctx可用于取消请求或设置超时
+func (c *ClientV2) CreateListMessageContext(ctx context.Context, req *PushRequestV2) (taskId string, err error) {
+	var respData struct {
+		TaskId string `json:"taskid"`
+	}
+	err = c.push(ctx, "push/list/message", req, 0, &respData)
+	return respData.TaskId, err
+}
+
+// 对cid列表执行tolist群推
+//  taskId	CreateListMessage 返回的任务号
+//  cidList	目标cid，每次最多1000个
+//  isAsync	是否异步推送，异步推送不返回推送结果
+func (c *ClientV2) PushListCid(taskId string, cidList []string, isAsync bool) (PushResultV2, error) {
+	return c.PushListCidContext(context.Background(), taskId, cidList, isAsync)
+}
+
+// 同 PushListCid，ctx可用于取消请求或设置超时
+func (c *ClientV2) PushListCidContext(ctx context.Context, taskId string, cidList []string, isAsync bool) (result PushResultV2, err error) {
+	if !c.noValidate {
+		if err = validatePushListV2(taskId, cidList); err != nil {
+			return
+		}
+	}
+	if err = c.reserve(len(cidList)); err != nil {
+		return
+	}
+
+	body, _ := json.Marshal(struct {
+		Audience AudienceV2 `json:"audience"`
+		TaskId   string     `json:"taskid"`
+		IsAsync  bool       `json:"is_async"`
+	}{AudienceV2{Cid: cidList}, taskId, isAsync})
+
+	err = c.requestWithAuth(ctx, "POST", "push/list/cid", string(body), &result)
+	c.settle(len(cidList), err)
+	return
+}
+
+// 对app所有用户群推
+//  req.Audience 会被忽略
+func (c *ClientV2) PushAll(req *PushRequestV2) (taskId string, err error) {
+	return c.PushAllContext(context.Background(), req)
+}
+
+// 同 PushAll，ctx可用于取消请求或设置超时
+func (c *ClientV2) PushAllContext(ctx context.Context, req *PushRequestV2) (taskId string, err error) {
+	// 群推全部用户时audience为字符串"all"
+	req = req.outgoing()
+	body, err := json.Marshal(struct {
+		*PushRequestV2
+		Audience string `json:"audience"`
+	}{req, "all"})
+	if err != nil {
+		return
+	}
+
+	if err = c.reserve(1); err != nil {
+		return
+	}
+
+	var respData struct {
+		TaskId string `json:"taskid"`
+	}
+	err = c.requestWithAuth(ctx, "POST", "push/all", string(body), &respData)
+	c.settle(1, err)
+	return respData.TaskId, withRequestId(err, req.RequestId)
+}
+
+// 根据条件筛选用户群推
+//  req.Audience.Tag 为筛选条件
+func (c *ClientV2) PushTag(req *PushRequestV2) (taskId string, err error) {
+	return c.PushTagContext(context.Background(), req)
+}
+
+// 同 PushTag，ctx可用于取消请求或设置超时
+func (c *ClientV2) PushTagContext(ctx context.Context, req *PushRequestV2) (taskId string, err error) {
+	var respData struct {
+		TaskId string `json:"taskid"`
+	}
+	err = c.push(ctx, "push/tag", req, 1, &respData)
+	return respData.TaskId, err
+}
@@ -0,0 +1,185 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/litinghong/GeTuiGoClient/getuitest"
+)
+
+const testCid = "44b4da5e84150d87ea1509442d41e175"
+
+func getClientV2(t *testing.T) *ClientV2 {
+	client, err := NewClientV2("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2",
+		WithBaseURL(fakeServer.BaseURLV2()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func newPushRequestV2(title string) *PushRequestV2 {
+	return &PushRequestV2{
+		Settings: &SettingsV2{TTL: 3600000},
+		PushMessage: &PushMessageV2{
+			Notification: &NotificationV2{
+				Title:     title,
+				Body:      "测试body",
+				ClickType: ClickTypeStartApp,
+			},
+		},
+	}
+}
+
+func TestClientV2_SinglePushCid(t *testing.T) {
+	client := getClientV2(t)
+
+	req := newPushRequestV2("SinglePushCid")
+	req.Audience = &AudienceV2{Cid: []string{testCid}}
+	result, err := client.SinglePushCid(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 1 || req.RequestId != "" {
+		t.Fatalf("unexpected result %v, request id %q", result, req.RequestId)
+	}
+	for _, status := range result {
+		if status[testCid] != ResultSuccessOnline {
+			t.Fatalf("unexpected result %v", result)
+		}
+	}
+}
+
+func TestClientV2_PushListCid(t *testing.T) {
+	client := getClientV2(t)
+
+	taskId, err := client.CreateListMessage(newPushRequestV2("PushListCid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.PushListCid(taskId, []string{testCid}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result[taskId][testCid] != ResultSuccessOnline {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestClientV2_PushAllAndTag(t *testing.T) {
+	client := getClientV2(t)
+
+	taskId, err := client.PushAll(newPushRequestV2("PushAll"))
+	if err != nil || taskId == "" {
+		t.Fatalf("unexpected result %s, %v", taskId, err)
+	}
+
+	req := newPushRequestV2("PushTag")
+	req.Audience = &AudienceV2{Tag: []TagV2{{Key: "custom_tag", Values: []string{"vip"}, OptType: "or"}}}
+	taskId, err = client.PushTag(req)
+	if err != nil || taskId == "" {
+		t.Fatalf("unexpected result %s, %v", taskId, err)
+	}
+}
+
+func TestClientV2_Errors(t *testing.T) {
+	client := getClientV2(t)
+
+	// token失效时刷新后重发
+	fakeServer.ExpireTokens()
+	req := newPushRequestV2("Errors")
+	req.Audience = &AudienceV2{Cid: []string{testCid}}
+	if _, err := client.SinglePushCid(req); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeServer.Fail("push/single/cid", getuitest.Failure{Code: CodeV2TooFrequent, Desc: "too frequent", Times: 1})
+	req = newPushRequestV2("Errors")
+	req.Audience = &AudienceV2{Cid: []string{testCid}}
+	_, err := client.SinglePushCid(req)
+
+	var apiErr *APIError
+	if !errors.Is(err, ErrTooFrequent) || !errors.As(err, &apiErr) {
+		t.Fatalf("expected ErrTooFrequent, got %v", err)
+	}
+	if apiErr.Code != CodeV2TooFrequent || apiErr.Endpoint != "push/single/cid" || apiErr.RequestId == "" {
+		t.Fatalf("unexpected error fields %+v", apiErr)
+	}
+}
+
+func TestClientV2_Validate(t *testing.T) {
+	client := getClientV2(t)
+
+	cids := make([]string, maxPushListTargets+1)
+	for i := range cids {
+		cids[i] = fmt.Sprintf("v2-validate-cid%d", i)
+	}
+	twoCids := newPushRequestV2("Validate")
+	twoCids.Audience = &AudienceV2{Cid: []string{testCid, testCid}}
+	batch := make([]*PushRequestV2, maxSinglePushBatch+1)
+	for i := range batch {
+		batch[i] = newPushRequestV2("Validate")
+		batch[i].Audience = &AudienceV2{Cid: []string{testCid}}
+	}
+
+	tests := []struct {
+		name string
+		send func() error
+	}{
+		{"single cid without audience", func() error {
+			_, err := client.SinglePushCid(newPushRequestV2("Validate"))
+			return err
+		}},
+		{"single cid with two cids", func() error {
+			_, err := client.SinglePushCid(twoCids)
+			return err
+		}},
+		{"single alias without alias", func() error {
+			_, err := client.SinglePushAlias(twoCids)
+			return err
+		}},
+		{"batch too large", func() error {
+			_, err := client.SinglePushBatchCid(batch, false)
+			return err
+		}},
+		{"batch with two cids", func() error {
+			_, err := client.SinglePushBatchCid([]*PushRequestV2{batch[0], twoCids}, false)
+			return err
+		}},
+		{"list without taskid", func() error {
+			_, err := client.PushListCid("", []string{testCid}, false)
+			return err
+		}},
+		{"list without cids", func() error {
+			_, err := client.PushListCid("task", nil, false)
+			return err
+		}},
+		{"list too many cids", func() error {
+			_, err := client.PushListCid("task", cids, false)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.send(); !errors.Is(err, ErrInvalidParam) {
+			t.Errorf("%s: expected ErrInvalidParam, got %v", tt.name, err)
+		}
+	}
+
+	// 发送时不修改调用方的请求
+	req := newPushRequestV2("PushAll")
+	before := *req
+	if _, err := client.PushAll(req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SinglePushBatchCid(batch[:2], false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, *req) || batch[0].RequestId != "" {
+		t.Fatal("push should not modify the request")
+	}
+}
@@ -0,0 +1,95 @@
+package GeTuiGo
+
+import (
+	"strings"
+	"sync"
+)
+
+// 省市信息，由 LookupRegion、FindRegions、Provinces 返回，不要修改
+type RegionInfo struct {
+	Code   string // 8位编码，用于 Region 筛选条件
+	Name   string // 全称，如 浙江省、杭州市
+	Short  string // 简称，如 浙江、杭州
+	Pinyin string // 简称的拼音，不带声调，如 zhejiang
+
+	province *RegionInfo
+	cities   []*RegionInfo
+}
+
+// 是否为省级，包括直辖市、自治区和特别行政区
+func (r *RegionInfo) IsProvince() bool {
+	return r.province == nil
+}
+
+// 所属省份，省级返回nil
+func (r *RegionInfo) Province() *RegionInfo {
+	return r.province
+}
+
+// 下属的市，直辖市和市级返回空
+func (r *RegionInfo) Cities() []*RegionInfo {
+	return append([]*RegionInfo(nil), r.cities...)
+}
+
+// 省市编码表，第一次使用时从 regionData 解析
+type regionCatalog struct {
+	provinces []*RegionInfo
+	byCode    map[string]*RegionInfo
+	byName    map[string][]*RegionInfo // 全称、简称和拼音
+}
+
+var (
+	regionsOnce sync.Once
+	regions     *regionCatalog
+)
+
+func loadRegions() *regionCatalog {
+	regionsOnce.Do(func() {
+		catalog := &regionCatalog{
+			byCode: make(map[string]*RegionInfo),
+			byName: make(map[string][]*RegionInfo),
+		}
+		for _, line := range strings.Split(strings.TrimSpace(regionData), "\n") {
+			f := strings.Fields(line)
+			r := &RegionInfo{Code: f[0], Name: f[1], Short: f[2], Pinyin: f[3]}
+			if strings.HasSuffix(r.Code, "000000") {
+				catalog.provinces = append(catalog.provinces, r)
+			} else {
+				r.province = catalog.byCode[r.Code[:2]+"000000"]
+				r.province.cities = append(r.province.cities, r)
+			}
+
+			catalog.byCode[r.Code] = r
+			for _, name := range []string{r.Name, r.Short, r.Pinyin} {
+				catalog.byName[name] = append(catalog.byName[name], r)
+			}
+		}
+		regions = catalog
+	})
+	return regions
+}
+
+// 按编码查找省市
+func LookupRegion(code string) (*RegionInfo, bool) {
+	r, ok := loadRegions().byCode[code]
+	return r, ok
+}
+
+// 按编码、全称、简称或拼音查找省市，拼音不区分大小写
+//  同名或同音时返回多个，如 吉林 对应吉林省和吉林市，suzhou 对应苏州市和宿州市
+func FindRegions(query string) []*RegionInfo {
+	catalog := loadRegions()
+	query = strings.TrimSpace(query)
+	if r, ok := catalog.byCode[query]; ok {
+		return []*RegionInfo{r}
+	}
+	if list, ok := catalog.byName[query]; ok {
+		return append([]*RegionInfo(nil), list...)
+	}
+	return append([]*RegionInfo(nil), catalog.byName[strings.ToLower(query)]...)
+}
+
+// 所有省级行政区，按编码排序
+func Provinces() []*RegionInfo {
+	return append([]*RegionInfo(nil), loadRegions().provinces...)
+}
@@ -0,0 +1,373 @@
+package GeTuiGo
+
+// 个推省市编码表，编码为国家行政区划代码的前4位补零到8位
+//  每行为：编码 全称 简称 拼音，市的编码前2位与所属省份相同
+const regionData = `
+11000000 北京市 北京 beijing
+12000000 天津市 天津 tianjin
+13000000 河北省 河北 hebei
+13010000 石家庄市 石家庄 shijiazhuang
+13020000 唐山市 唐山 tangshan
+13030000 秦皇岛市 秦皇岛 qinhuangdao
+13040000 邯郸市 邯郸 handan
+13050000 邢台市 邢台 xingtai
+13060000 保定市 保定 baoding
+13070000 张家口市 张家口 zhangjiakou
+13080000 承德市 承德 chengde
+13090000 沧州市 沧州 cangzhou
+13100000 廊坊市 廊坊 langfang
+13110000 衡水市 衡水 hengshui
+14000000 山西省 山西 shanxi
+14010000 太原市 太原 taiyuan
+14020000 大同市 大同 datong
+14030000 阳泉市 阳泉 yangquan
+14040000 长治市 长治 changzhi
+14050000 晋城市 晋城 jincheng
+14060000 朔州市 朔州 shuozhou
+14070000 晋中市 晋中 jinzhong
+14080000 运城市 运城 yuncheng
+14090000 忻州市 忻州 xinzhou
+14100000 临汾市 临汾 linfen
+14110000 吕梁市 吕梁 lvliang
+15000000 内蒙古自治区 内蒙古 neimenggu
+15010000 呼和浩特市 呼和浩特 huhehaote
+15020000 包头市 包头 baotou
+15030000 乌海市 乌海 wuhai
+15040000 赤峰市 赤峰 chifeng
+15050000 通辽市 通辽 tongliao
+15060000 鄂尔多斯市 鄂尔多斯 eerduosi
+15070000 呼伦贝尔市 呼伦贝尔 hulunbeier
+15080000 巴彦淖尔市 巴彦淖尔 bayannaoer
+15090000 乌兰察布市 乌兰察布 wulanchabu
+15220000 兴安盟 兴安 xingan
+15250000 锡林郭勒盟 锡林郭勒 xilinguole
+15290000 阿拉善盟 阿拉善 alashan
+21000000 辽宁省 辽宁 liaoning
+21010000 沈阳市 沈阳 shenyang
+21020000 大连市 大连 dalian
+21030000 鞍山市 鞍山 anshan
+21040000 抚顺市 抚顺 fushun
+21050000 本溪市 本溪 benxi
+21060000 丹东市 丹东 dandong
+21070000 锦州市 锦州 jinzhou
+21080000 营口市 营口 yingkou
+21090000 阜新市 阜新 fuxin
+21100000 辽阳市 辽阳 liaoyang
+21110000 盘锦市 盘锦 panjin
+21120000 铁岭市 铁岭 tieling
+21130000 朝阳市 朝阳 chaoyang
+21140000 葫芦岛市 葫芦岛 huludao
+22000000 吉林省 吉林 jilin
+22010000 长春市 长春 changchun
+22020000 吉林市 吉林 jilin
+22030000 四平市 四平 siping
+22040000 辽源市 辽源 liaoyuan
+22050000 通化市 通化 tonghua
+22060000 白山市 白山 baishan
+22070000 松原市 松原 songyuan
+22080000 白城市 白城 baicheng
+22240000 延边朝鲜族自治州 延边 yanbian
+23000000 黑龙江省 黑龙江 heilongjiang
+23010000 哈尔滨市 哈尔滨 haerbin
+23020000 齐齐哈尔市 齐齐哈尔 qiqihaer
+23030000 鸡西市 鸡西 jixi
+23040000 鹤岗市 鹤岗 hegang
+23050000 双鸭山市 双鸭山 shuangyashan
+23060000 大庆市 大庆 daqing
+23070000 伊春市 伊春 yichun
+23080000 佳木斯市 佳木斯 jiamusi
+23090000 七台河市 七台河 qitaihe
+23100000 牡丹江市 牡丹江 mudanjiang
+23110000 黑河市 黑河 heihe
+23120000 绥化市 绥化 suihua
+23270000 大兴安岭地区 大兴安岭 daxinganling
+31000000 上海市 上海 shanghai
+32000000 江苏省 江苏 jiangsu
+32010000 南京市 南京 nanjing
+32020000 无锡市 无锡 wuxi
+32030000 徐州市 徐州 xuzhou
+32040000 常州市 常州 changzhou
+32050000 苏州市 苏州 suzhou
+32060000 南通市 南通 nantong
+32070000 连云港市 连云港 lianyungang
+32080000 淮安市 淮安 huaian
+32090000 盐城市 盐城 yancheng
+32100000 扬州市 扬州 yangzhou
+32110000 镇江市 镇江 zhenjiang
+32120000 泰州市 泰州 taizhou
+32130000 宿迁市 宿迁 suqian
+33000000 浙江省 浙江 zhejiang
+33010000 杭州市 杭州 hangzhou
+33020000 宁波市 宁波 ningbo
+33030000 温州市 温州 wenzhou
+33040000 嘉兴市 嘉兴 jiaxing
+33050000 湖州市 湖州 huzhou
+33060000 绍兴市 绍兴 shaoxing
+33070000 金华市 金华 jinhua
+33080000 衢州市 衢州 quzhou
+33090000 舟山市 舟山 zhoushan
+33100000 台州市 台州 taizhou
+33110000 丽水市 丽水 lishui
+34000000 安徽省 安徽 anhui
+34010000 合肥市 合肥 hefei
+34020000 芜湖市 芜湖 wuhu
+34030000 蚌埠市 蚌埠 bengbu
+34040000 淮南市 淮南 huainan
+34050000 马鞍山市 马鞍山 maanshan
+34060000 淮北市 淮北 huaibei
+34070000 铜陵市 铜陵 tongling
+34080000 安庆市 安庆 anqing
+34100000 黄山市 黄山 huangshan
+34110000 滁州市 滁州 chuzhou
+34120000 阜阳市 阜阳 fuyang
+34130000 宿州市 宿州 suzhou
+34150000 六安市 六安 luan
+34160000 亳州市 亳州 bozhou
+34170000 池州市 池州 chizhou
+34180000 宣城市 宣城 xuancheng
+35000000 福建省 福建 fujian
+35010000 福州市 福州 fuzhou
+35020000 厦门市 厦门 xiamen
+35030000 莆田市 莆田 putian
+35040000 三明市 三明 sanming
+35050000 泉州市 泉州 quanzhou
+35060000 漳州市 漳州 zhangzhou
+35070000 南平市 南平 nanping
+35080000 龙岩市 龙岩 longyan
+35090000 宁德市 宁德 ningde
+36000000 江西省 江西 jiangxi
+36010000 南昌市 南昌 nanchang
+36020000 景德镇市 景德镇 jingdezhen
+36030000 萍乡市 萍乡 pingxiang
+36040000 九江市 九江 jiujiang
+36050000 新余市 新余 xinyu
+36060000 鹰潭市 鹰潭 yingtan
+36070000 赣州市 赣州 ganzhou
+36080000 吉安市 吉安 jian
+36090000 宜春市 宜春 yichun
+36100000 抚州市 抚州 fuzhou
+36110000 上饶市 上饶 shangrao
+37000000 山东省 山东 shandong
+37010000 济南市 济南 jinan
+37020000 青岛市 青岛 qingdao
+37030000 淄博市 淄博 zibo
+37040000 枣庄市 枣庄 zaozhuang
+37050000 东营市 东营 dongying
+37060000 烟台市 烟台 yantai
+37070000 潍坊市 潍坊 weifang
+37080000 济宁市 济宁 jining
+37090000 泰安市 泰安 taian
+37100000 威海市 威海 weihai
+37110000 日照市 日照 rizhao
+37130000 临沂市 临沂 linyi
+37140000 德州市 德州 dezhou
+37150000 聊城市 聊城 liaocheng
+37160000 滨州市 滨州 binzhou
+37170000 菏泽市 菏泽 heze
+41000000 河南省 河南 henan
+41010000 郑州市 郑州 zhengzhou
+41020000 开封市 开封 kaifeng
+41030000 洛阳市 洛阳 luoyang
+41040000 平顶山市 平顶山 pingdingshan
+41050000 安阳市 安阳 anyang
+41060000 鹤壁市 鹤壁 hebi
+41070000 新乡市 新乡 xinxiang
+41080000 焦作市 焦作 jiaozuo
+41090000 濮阳市 濮阳 puyang
+41100000 许昌市 许昌 xuchang
+41110000 漯河市 漯河 luohe
+41120000 三门峡市 三门峡 sanmenxia
+41130000 南阳市 南阳 nanyang
+41140000 商丘市 商丘 shangqiu
+41150000 信阳市 信阳 xinyang
+41160000 周口市 周口 zhoukou
+41170000 驻马店市 驻马店 zhumadian
+42000000 湖北省 湖北 hubei
+42010000 武汉市 武汉 wuhan
+42020000 黄石市 黄石 huangshi
+42030000 十堰市 十堰 shiyan
+42050000 宜昌市 宜昌 yichang
+42060000 襄阳市 襄阳 xiangyang
+42070000 鄂州市 鄂州 ezhou
+42080000 荆门市 荆门 jingmen
+42090000 孝感市 孝感 xiaogan
+42100000 荆州市 荆州 jingzhou
+42110000 黄冈市 黄冈 huanggang
+42120000 咸宁市 咸宁 xianning
+42130000 随州市 随州 suizhou
+42280000 恩施土家族苗族自治州 恩施 enshi
+43000000 湖南省 湖南 hunan
+43010000 长沙市 长沙 changsha
+43020000 株洲市 株洲 zhuzhou
+43030000 湘潭市 湘潭 xiangtan
+43040000 衡阳市 衡阳 hengyang
+43050000 邵阳市 邵阳 shaoyang
+43060000 岳阳市 岳阳 yueyang
+43070000 常德市 常德 changde
+43080000 张家界市 张家界 zhangjiajie
+43090000 益阳市 益阳 yiyang
+43100000 郴州市 郴州 chenzhou
+43110000 永州市 永州 yongzhou
+43120000 怀化市 怀化 huaihua
+43130000 娄底市 娄底 loudi
+43310000 湘西土家族苗族自治州 湘西 xiangxi
+44000000 广东省 广东 guangdong
+44010000 广州市 广州 guangzhou
+44020000 韶关市 韶关 shaoguan
+44030000 深圳市 深圳 shenzhen
+44040000 珠海市 珠海 zhuhai
+44050000 汕头市 汕头 shantou
+44060000 佛山市 佛山 foshan
+44070000 江门市 江门 jiangmen
+44080000 湛江市 湛江 zhanjiang
+44090000 茂名市 茂名 maoming
+44120000 肇庆市 肇庆 zhaoqing
+44130000 惠州市 惠州 huizhou
+44140000 梅州市 梅州 meizhou
+44150000 汕尾市 汕尾 shanwei
+44160000 河源市 河源 heyuan
+44170000 阳江市 阳江 yangjiang
+44180000 清远市 清远 qingyuan
+44190000 东莞市 东莞 dongguan
+44200000 中山市 中山 zhongshan
+44510000 潮州市 潮州 chaozhou
+44520000 揭阳市 揭阳 jieyang
+44530000 云浮市 云浮 yunfu
+45000000 广西壮族自治区 广西 guangxi
+45010000 南宁市 南宁 nanning
+45020000 柳州市 柳州 liuzhou
+45030000 桂林市 桂林 guilin
+45040000 梧州市 梧州 wuzhou
+45050000 北海市 北海 beihai
+45060000 防城港市 防城港 fangchenggang
+45070000 钦州市 钦州 qinzhou
+45080000 贵港市 贵港 guigang
+45090000 玉林市 玉林 yulin
+45100000 百色市 百色 baise
+45110000 贺州市 贺州 hezhou
+45120000 河池市 河池 hechi
+45130000 来宾市 来宾 laibin
+45140000 崇左市 崇左 chongzuo
+46000000 海南省 海南 hainan
+46010000 海口市 海口 haikou
+46020000 三亚市 三亚 sanya
+46030000 三沙市 三沙 sansha
+46040000 儋州市 儋州 danzhou
+50000000 重庆市 重庆 chongqing
+51000000 四川省 四川 sichuan
+51010000 成都市 成都 chengdu
+51030000 自贡市 自贡 zigong
+51040000 攀枝花市 攀枝花 panzhihua
+51050000 泸州市 泸州 luzhou
+51060000 德阳市 德阳 deyang
+51070000 绵阳市 绵阳 mianyang
+51080000 广元市 广元 guangyuan
+51090000 遂宁市 遂宁 suining
+51100000 内江市 内江 neijiang
+51110000 乐山市 乐山 leshan
+51130000 南充市 南充 nanchong
+51140000 眉山市 眉山 meishan
+51150000 宜宾市 宜宾 yibin
+51160000 广安市 广安 guangan
+51170000 达州市 达州 dazhou
+51180000 雅安市 雅安 yaan
+51190000 巴中市 巴中 bazhong
+51200000 资阳市 资阳 ziyang
+51320000 阿坝藏族羌族自治州 阿坝 aba
+51330000 甘孜藏族自治州 甘孜 ganzi
+51340000 凉山彝族自治州 凉山 liangshan
+52000000 贵州省 贵州 guizhou
+52010000 贵阳市 贵阳 guiyang
+52020000 六盘水市 六盘水 liupanshui
+52030000 遵义市 遵义 zunyi
+52040000 安顺市 安顺 anshun
+52050000 毕节市 毕节 bijie
+52060000 铜仁市 铜仁 tongren
+52230000 黔西南布依族苗族自治州 黔西南 qianxinan
+52260000 黔东南苗族侗族自治州 黔东南 qiandongnan
+52270000 黔南布依族苗族自治州 黔南 qiannan
+53000000 云南省 云南 yunnan
+53010000 昆明市 昆明 kunming
+53030000 曲靖市 曲靖 qujing
+53040000 玉溪市 玉溪 yuxi
+53050000 保山市 保山 baoshan
+53060000 昭通市 昭通 zhaotong
+53070000 丽江市 丽江 lijiang
+53080000 普洱市 普洱 puer
+53090000 临沧市 临沧 lincang
+53230000 楚雄彝族自治州 楚雄 chuxiong
+53250000 红河哈尼族彝族自治州 红河 honghe
+53260000 文山壮族苗族自治州 文山 wenshan
+53280000 西双版纳傣族自治州 西双版纳 xishuangbanna
+53290000 大理白族自治州 大理 dali
+53310000 德宏傣族景颇族自治州 德宏 dehong
+53330000 怒江傈僳族自治州 怒江 nujiang
+53340000 迪庆藏族自治州 迪庆 diqing
+54000000 西藏自治区 西藏 xizang
+54010000 拉萨市 拉萨 lasa
+54020000 日喀则市 日喀则 rikaze
+54030000 昌都市 昌都 changdu
+54040000 林芝市 林芝 linzhi
+54050000 山南市 山南 shannan
+54060000 那曲市 那曲 naqu
+54250000 阿里地区 阿里 ali
+61000000 陕西省 陕西 shaanxi
+61010000 西安市 西安 xian
+61020000 铜川市 铜川 tongchuan
+61030000 宝鸡市 宝鸡 baoji
+61040000 咸阳市 咸阳 xianyang
+61050000 渭南市 渭南 weinan
+61060000 延安市 延安 yanan
+61070000 汉中市 汉中 hanzhong
+61080000 榆林市 榆林 yulin
+61090000 安康市 安康 ankang
+61100000 商洛市 商洛 shangluo
+62000000 甘肃省 甘肃 gansu
+62010000 兰州市 兰州 lanzhou
+62020000 嘉峪关市 嘉峪关 jiayuguan
+62030000 金昌市 金昌 jinchang
+62040000 白银市 白银 baiyin
+62050000 天水市 天水 tianshui
+62060000 武威市 武威 wuwei
+62070000 张掖市 张掖 zhangye
+62080000 平凉市 平凉 pingliang
+62090000 酒泉市 酒泉 jiuquan
+62100000 庆阳市 庆阳 qingyang
+62110000 定西市 定西 dingxi
+62120000 陇南市 陇南 longnan
+62290000 临夏回族自治州 临夏 linxia
+62300000 甘南藏族自治州 甘南 gannan
+63000000 青海省 青海 qinghai
+63010000 西宁市 西宁 xining
+63020000 海东市 海东 haidong
+63220000 海北藏族自治州 海北 haibei
+63230000 黄南藏族自治州 黄南 huangnan
+63250000 海南藏族自治州 海南州 hainanzhou
+63260000 果洛藏族自治州 果洛 guoluo
+63270000 玉树藏族自治州 玉树 yushu
+63280000 海西蒙古族藏族自治州 海西 haixi
+64000000 宁夏回族自治区 宁夏 ningxia
+64010000 银川市 银川 yinchuan
+64020000 石嘴山市 石嘴山 shizuishan
+64030000 吴忠市 吴忠 wuzhong
+64040000 固原市 固原 guyuan
+64050000 中卫市 中卫 zhongwei
+65000000 新疆维吾尔自治区 新疆 xinjiang
+65010000 乌鲁木齐市 乌鲁木齐 wulumuqi
+65020000 克拉玛依市 克拉玛依 kelamayi
+65040000 吐鲁番市 吐鲁番 tulufan
+65050000 哈密市 哈密 hami
+65230000 昌吉回族自治州 昌吉 changji
+65270000 博尔塔拉蒙古自治州 博尔塔拉 boertala
+65280000 巴音郭楞蒙古自治州 巴音郭楞 bayinguoleng
+65290000 阿克苏地区 阿克苏 akesu
+65300000 克孜勒苏柯尔克孜自治州 克孜勒苏 kezilesu
+65310000 喀什地区 喀什 kashi
+65320000 和田地区 和田 hetian
+65400000 伊犁哈萨克自治州 伊犁 yili
+65420000 塔城地区 塔城 tacheng
+65430000 阿勒泰地区 阿勒泰 aletai
+71000000 台湾省 台湾 taiwan
+81000000 香港特别行政区 香港 xianggang
+82000000 澳门特别行政区 澳门 aomen
+`
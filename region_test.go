@@ -0,0 +1,73 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFindRegions(t *testing.T) {
+	tests := []struct {
+		query string
+		codes []string
+	}{
+		{"33010000", []string{"33010000"}},
+		{"杭州市", []string{"33010000"}},
+		{"杭州", []string{"33010000"}},
+		{"HangZhou", []string{"33010000"}},
+		{"吉林", []string{"22000000", "22020000"}},
+		{"suzhou", []string{"32050000", "34130000"}},
+		{"不存在", nil},
+	}
+
+	for _, tt := range tests {
+		var codes []string
+		for _, r := range FindRegions(tt.query) {
+			codes = append(codes, r.Code)
+		}
+		if !reflect.DeepEqual(codes, tt.codes) {
+			t.Errorf("%s: got %v, want %v", tt.query, codes, tt.codes)
+		}
+	}
+}
+
+func TestRegion_Hierarchy(t *testing.T) {
+	provinces := Provinces()
+	if len(provinces) != 34 {
+		t.Fatalf("expected 34 provinces, got %d", len(provinces))
+	}
+
+	zhejiang, ok := LookupRegion("33000000")
+	if !ok || !zhejiang.IsProvince() || zhejiang.Name != "浙江省" {
+		t.Fatalf("unexpected region %+v", zhejiang)
+	}
+	cities := zhejiang.Cities()
+	if len(cities) != 11 || cities[0].Short != "杭州" {
+		t.Fatalf("unexpected cities %v", cities)
+	}
+	if cities[0].Province() != zhejiang || cities[0].IsProvince() {
+		t.Fatal("city should belong to its province")
+	}
+
+	beijing, _ := LookupRegion("11000000")
+	if len(beijing.Cities()) != 0 {
+		t.Fatal("municipality should have no cities")
+	}
+}
+
+func TestRegion_Condition(t *testing.T) {
+	if err := Region("33010000", "11000000").Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Region("33019999").Validate(); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected error for an unknown region code, got %v", err)
+	}
+
+	conds, err := ParseConditions("region:杭州 OR region:shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(conds, []Condition{Region("33010000", "31000000")}) {
+		t.Fatalf("unexpected conditions %+v", conds)
+	}
+}
@@ -0,0 +1,78 @@
+package GeTuiGo
+
+import (
+	"context"
+)
+
+// 任务组的推送报告，由 GetGroupReport 返回
+type GroupReport struct {
+	GroupName string             // 任务组名
+	Group     PushResultByGroup  // 按任务组名查询的整组数据
+	Tasks     []PushResultDetail // 各任务的推送结果，按传入的任务号排序，不包含查不到的任务
+	Missing   []string           // 查不到推送结果的任务号
+	Totals    PushResultDetail   // 各任务推送结果的合计，TaskId为空
+}
+
+// 累加推送结果的各项数据
+func (d *PushResultDetail) add(other PushResultDetail) {
+	d.MsgTotal += other.MsgTotal
+	d.MsgProcess += other.MsgProcess
+	d.ClickNum += other.ClickNum
+	d.PushNum += other.PushNum
+	d.Apn.Displayed += other.Apn.Displayed
+	d.Apn.Feedback += other.Apn.Feedback
+	d.Apn.Clicked += other.Apn.Clicked
+	d.Apn.Sent += other.Apn.Sent
+	d.GT.Sent += other.GT.Sent
+	d.GT.Feedback += other.GT.Feedback
+	d.GT.Clicked += other.GT.Clicked
+	d.GT.Displayed += other.GT.Displayed
+}
+
+// 查询任务组的推送报告，合并 GetPushResultByGroup 的整组数据和 GetPushResult 的各任务数据
+//  groupName	任务组名，即 Push.SetTaskName 设置的名称
+//  taskIds	组内的任务号，如 SinglePush、SaveListBody、PushToApp 返回的taskId，为空时只查询整组数据，
+//  		重复的任务号只查询和合计一次，超过单次查询上限时分批查询
+func (c *Client) GetGroupReport(groupName string, taskIds []string) (report GroupReport, err error) {
+	return c.GetGroupReportContext(context.Background(), groupName, taskIds)
+}
+
+// 同 GetGroupReport，ctx可用于取消请求或设置超时
+func (c *Client) GetGroupReportContext(ctx context.Context, groupName string, taskIds []string) (report GroupReport, err error) {
+	report.GroupName = groupName
+	if report.Group, err = c.GetPushResultByGroupContext(ctx, groupName); err != nil {
+		return
+	}
+
+	// 去掉重复的任务号，避免重复合计
+	seen := make(map[string]bool, len(taskIds))
+	unique := make([]string, 0, len(taskIds))
+	for _, taskId := range taskIds {
+		if !seen[taskId] {
+			seen[taskId] = true
+			unique = append(unique, taskId)
+		}
+	}
+	taskIds = unique
+
+	byTaskId := make(map[string]PushResultDetail, len(taskIds))
+	for _, chunk := range splitChunks(taskIds, maxPushResultTasks) {
+		_, details, err := c.GetPushResultContext(ctx, chunk)
+		if err != nil {
+			return report, err
+		}
+		for _, detail := range details {
+			byTaskId[detail.TaskId] = detail
+		}
+	}
+	for _, taskId := range taskIds {
+		detail, ok := byTaskId[taskId]
+		if !ok {
+			report.Missing = append(report.Missing, taskId)
+			continue
+		}
+		report.Tasks = append(report.Tasks, detail)
+		report.Totals.add(detail)
+	}
+	return
+}
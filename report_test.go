@@ -0,0 +1,95 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClient_GetGroupReport(t *testing.T) {
+	client := getClient(t)
+
+	var taskIds []string
+	for i := 0; i < 2; i++ {
+		push, err := NewPush().ToCid(testCid).Transmission(TmplTransmission{TransmissionContent: "group"}).TaskName("报告_group-1").Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := client.SinglePush(push)
+		if err != nil {
+			t.Fatal(err)
+		}
+		taskIds = append(taskIds, result.TaskId)
+	}
+
+	// 重复的任务号只合计一次
+	report, err := client.GetGroupReport("报告_group-1", append(taskIds, "missing-task", taskIds[0], "missing-task"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Tasks) != 2 || report.Tasks[0].TaskId != taskIds[0] || report.Tasks[1].TaskId != taskIds[1] {
+		t.Fatalf("unexpected tasks %+v", report.Tasks)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "missing-task" {
+		t.Fatalf("unexpected missing tasks %v", report.Missing)
+	}
+	if report.Totals.PushNum != 2 || report.Group.OnlineNum != report.Totals.PushNum {
+		t.Fatalf("unexpected totals %+v, group %+v", report.Totals, report.Group)
+	}
+}
+
+func TestPush_TaskName(t *testing.T) {
+	push := &Push{Template: TmplTransmission{TransmissionContent: "task name"}}
+	push.SetTaskName("group/1")
+	if err := push.Validate(); err == nil || !strings.Contains(err.Error(), "task_name") {
+		t.Fatalf("expected task_name error, got %v", err)
+	}
+	push.SetTaskName(strings.Repeat("组", maxTaskNameLength))
+	if err := push.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(push.ToJsonString("appKey"), `"task_name":"`+push.TaskName()+`"`) {
+		t.Fatal("task_name should be serialized")
+	}
+
+	client := getClient(t)
+	if _, err := client.GetPushResultByGroup("a/b"); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected invalid param error, got %v", err)
+	}
+}
+
+func TestClient_GetGroupReport_Chunks(t *testing.T) {
+	client := getClient(t)
+
+	var pushList []*Push
+	for i := 0; i < maxPushResultTasks+20; i++ {
+		push, err := NewPush().ToCid(testCid).Transmission(TmplTransmission{TransmissionContent: "group"}).TaskName("report_chunks").Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pushList = append(pushList, push)
+	}
+	items, err := client.SinglePushBatchAll(pushList, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskIds := make([]string, len(items))
+	for i, item := range items {
+		taskIds[i] = item.TaskId
+	}
+
+	before := fakeServer.RequestCount("push_result")
+	report, err := client.GetGroupReport("report_chunks", taskIds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := fakeServer.RequestCount("push_result") - before; n != 2 {
+		t.Fatalf("expected 2 push_result requests, got %d", n)
+	}
+	if len(report.Tasks) != len(taskIds) || len(report.Missing) != 0 || report.Tasks[len(taskIds)-1].TaskId != taskIds[len(taskIds)-1] {
+		t.Fatalf("unexpected tasks %d, missing %v", len(report.Tasks), report.Missing)
+	}
+	if report.Totals.PushNum != len(taskIds) {
+		t.Fatalf("unexpected totals %+v", report.Totals)
+	}
+}
@@ -0,0 +1,145 @@
+package GeTuiGo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 请求失败时的重试策略
+//  网络错误总是可以重试，接口错误按 RetryResults 和 RetryStatuses 判断，响应解析失败等其他错误不重试
+//  推送接口每次重试使用相同的RequestId，由个推服务端去重；tolist群推没有去重，默认不重试
+type RetryPolicy struct {
+	MaxAttempts   int           // 最大尝试次数，包含第一次请求，小于等于1时不重试
+	BaseDelay     time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxDelay      time.Duration // 单次等待时间上限，0表示不限制
+	Jitter        float64       // 等待时间随机浮动的比例，取值0~1
+	RetryResults  []string      // 需要重试的响应结果，使用 ResultXXX 常量
+	RetryStatuses []int         // 需要重试的http状态码
+	RetryCodes    []int         // 需要重试的v2接口错误码，使用 CodeV2XXX 常量
+
+	RetryNonIdempotent bool // 是否重试没有去重的接口（tolist群推），请求已到达服务端但响应丢失时重试会重复推送
+}
+
+// 服务端不按RequestId去重的接口，重试可能重复推送
+var nonIdempotentEndpoints = map[string]bool{
+	"push_list":     true,
+	"push/list/cid": true,
+}
+
+// 默认重试策略：最多请求3次，推送过于频繁、其他错误、调用频率超限、429及5xx时重试
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		BaseDelay:    200 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		Jitter:       0.2,
+		RetryResults: []string{ResultTooFrequent, ResultOtherError},
+		RetryStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryCodes: []int{CodeV2AuthTooFrequent, CodeV2TooFrequent},
+	}
+}
+
+// 设置重试策略，默认不重试
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy
+	}
+}
+
+// 判断第attempt次请求失败后是否需要重试
+func (p *RetryPolicy) shouldRetry(ctx context.Context, endpoint string, attempt int, err error) bool {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if nonIdempotentEndpoints[endpoint] && !p.RetryNonIdempotent {
+		return false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return isTransportError(err)
+	}
+
+	for _, result := range p.RetryResults {
+		if apiErr.Result != "" && apiErr.Result == result {
+			return true
+		}
+	}
+	for _, status := range p.RetryStatuses {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	for _, code := range p.RetryCodes {
+		if apiErr.Code != 0 && apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 是否为网络错误
+func isTransportError(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// 第attempt次请求失败后的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay += time.Duration(float64(delay) * p.Jitter * (rand.Float64()*2 - 1))
+	}
+	return delay
+}
+
+// 按接口类别限流后发送请求，失败时按重试策略重试
+//  endpoint	接口名称
+//  attempt		发送一次请求，返回响应内容
+func (o *options) request(ctx context.Context, endpoint string, attempt func() ([]byte, error)) ([]byte, error) {
+	for n := 1; ; n++ {
+		// 重试的请求同样需要限流
+		if err := o.wait(ctx, endpoint); err != nil {
+			return nil, err
+		}
+		respBody, err := attempt()
+		if err == nil || !o.retry.shouldRetry(ctx, endpoint, n, err) {
+			return respBody, err
+		}
+		if err := sleepContext(ctx, o.retry.backoff(n)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// 等待一段时间，ctx结束时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,137 @@
+package GeTuiGo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Retry(t *testing.T) {
+	var mu sync.Mutex
+	var requestIds []string
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			RequestId string `json:"requestid"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		requestIds = append(requestIds, body.RequestId)
+		attempt := len(requestIds)
+		mu.Unlock()
+
+		switch attempt {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Write([]byte(`{"result":"too_frequent"}`))
+		default:
+			w.Write([]byte(`{"result":"ok","taskid":"task","status":"successed_online"}`))
+		}
+	}, WithRetryPolicy(policy))
+	defer server.Close()
+
+	result, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"})
+	if err != nil || result.TaskId != "task" {
+		t.Fatalf("unexpected result %v, %v", result, err)
+	}
+
+	if len(requestIds) != 3 || requestIds[0] == "" || requestIds[0] != requestIds[1] || requestIds[1] != requestIds[2] {
+		t.Fatalf("expected 3 attempts with the same requestid, got %v", requestIds)
+	}
+}
+
+func TestClient_RetryNotRetryable(t *testing.T) {
+	attempts := 0
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Write([]byte(`{"result":"no_user"}`))
+	}, WithRetryPolicy(policy))
+	defer server.Close()
+
+	_, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "test"}, Cid: "cid"})
+	if !errors.Is(err, ErrNoUser) || attempts != 1 {
+		t.Fatalf("unexpected error %v after %d attempts", err, attempts)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, d := range expected {
+		if got := policy.backoff(i + 1); got != d*time.Millisecond {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, d*time.Millisecond, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := policy.backoff(1); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("jitter out of range: %v", d)
+		}
+	}
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	ctx := context.Background()
+	transportErr := &url.Error{Op: "Post", URL: "https://restapi.getui.com", Err: errors.New("connection reset")}
+	tooFrequent := &APIError{Result: ResultTooFrequent}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		err      error
+		want     bool
+	}{
+		{"transport", "push_single", transportErr, true},
+		{"api error", "push_single", tooFrequent, true},
+		{"decode error", "push_single", &json.SyntaxError{}, false},
+		{"other error", "push_single", errors.New("read body"), false},
+		{"push_list transport", "push_list", transportErr, false},
+		{"push_list api error", "push_list", tooFrequent, false},
+		{"v2 list transport", "push/list/cid", transportErr, false},
+	}
+	for _, tt := range tests {
+		if got := policy.shouldRetry(ctx, tt.endpoint, 1, tt.err); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	policy.RetryNonIdempotent = true
+	if !policy.shouldRetry(ctx, "push_list", 1, transportErr) || !policy.shouldRetry(ctx, "push_list", 1, tooFrequent) {
+		t.Error("push_list should be retried when RetryNonIdempotent is set")
+	}
+}
+
+func TestClient_RetryPushList(t *testing.T) {
+	attempts := 0
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	for _, optIn := range []bool{false, true} {
+		policy.RetryNonIdempotent = optIn
+		client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, WithRetryPolicy(policy))
+		defer server.Close()
+
+		attempts = 0
+		want := 1
+		if optIn {
+			want = policy.MaxAttempts
+		}
+		if _, err := client.PushList(&PushList{TaskId: "task", Cid: []string{"cid"}}); err == nil || attempts != want {
+			t.Fatalf("opt-in %v: expected %d attempts, got %d (%v)", optIn, want, attempts, err)
+		}
+	}
+}
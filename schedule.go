@@ -0,0 +1,178 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 展示时间、定时下发时间的格式，即yyyy-MM-dd HH:mm:ss
+const timeLayout = "2006-01-02 15:04:05"
+
+// 个推定时任务与展示时间段的限制
+const (
+	MinScheduleLead  = 10 * time.Minute   // 定时下发时间至少在当前时间10分钟之后
+	MaxScheduleLead  = 7 * 24 * time.Hour // 定时下发时间最多在当前时间7天之内
+	MaxDisplayWindow = 72 * time.Hour     // 展示时间段最长72小时，与离线保存时间一致
+)
+
+// 个推服务端使用的时区，时间参数都按Asia/Shanghai格式化
+var Location = loadLocation()
+
+// 加载Asia/Shanghai时区，系统没有时区数据时使用固定的东八区
+func loadLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Shanghai"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*60*60)
+}
+
+// 按个推时区格式化为yyyy-MM-dd HH:mm:ss，零值返回空字符串
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(Location).Format(timeLayout)
+}
+
+// 解析个推时区的yyyy-MM-dd HH:mm:ss，空字符串返回零值
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(timeLayout, value, Location)
+}
+
+// 模板中的展示时间段，序列化为个推时区的yyyy-MM-dd HH:mm:ss
+type displayWindow struct {
+	DurationBegin string `json:"duration_begin"`
+	DurationEnd   string `json:"duration_end"`
+}
+
+func newDisplayWindow(begin, end time.Time) *displayWindow {
+	return &displayWindow{formatTime(begin), formatTime(end)}
+}
+
+func (w *displayWindow) times() (begin, end time.Time, err error) {
+	if begin, err = parseTime(w.DurationBegin); err != nil {
+		return
+	}
+	end, err = parseTime(w.DurationEnd)
+	return
+}
+
+func (t TmplNotification) MarshalJSON() ([]byte, error) {
+	type plain TmplNotification
+	return json.Marshal(struct {
+		plain
+		*displayWindow
+	}{plain(t), newDisplayWindow(t.DurationBegin, t.DurationEnd)})
+}
+
+// 按style中的type字段解析为对应的样式
+func (t *TmplNotification) UnmarshalJSON(data []byte) (err error) {
+	type plain TmplNotification
+	w := &displayWindow{}
+	aux := struct {
+		*plain
+		*displayWindow
+		Style json.RawMessage `json:"style"`
+	}{plain: (*plain)(t), displayWindow: w}
+	if err = json.Unmarshal(data, &aux); err != nil {
+		return
+	}
+	if t.Style, err = unmarshalStyle(aux.Style); err != nil {
+		return
+	}
+	t.DurationBegin, t.DurationEnd, err = w.times()
+	return
+}
+
+func (t TmplLink) MarshalJSON() ([]byte, error) {
+	type plain TmplLink
+	return json.Marshal(struct {
+		plain
+		*displayWindow
+	}{plain(t), newDisplayWindow(t.DurationBegin, t.DurationEnd)})
+}
+
+// 按style中的type字段解析为对应的样式
+func (t *TmplLink) UnmarshalJSON(data []byte) (err error) {
+	type plain TmplLink
+	w := &displayWindow{}
+	aux := struct {
+		*plain
+		*displayWindow
+		Style json.RawMessage `json:"style"`
+	}{plain: (*plain)(t), displayWindow: w}
+	if err = json.Unmarshal(data, &aux); err != nil {
+		return
+	}
+	if t.Style, err = unmarshalStyle(aux.Style); err != nil {
+		return
+	}
+	t.DurationBegin, t.DurationEnd, err = w.times()
+	return
+}
+
+func (t TmplNotifyPopLoad) MarshalJSON() ([]byte, error) {
+	type plain TmplNotifyPopLoad
+	return json.Marshal(struct {
+		plain
+		*displayWindow
+	}{plain(t), newDisplayWindow(t.DurationBegin, t.DurationEnd)})
+}
+
+func (t *TmplNotifyPopLoad) UnmarshalJSON(data []byte) (err error) {
+	type plain TmplNotifyPopLoad
+	w := &displayWindow{}
+	if err = json.Unmarshal(data, &struct {
+		*plain
+		*displayWindow
+	}{(*plain)(t), w}); err != nil {
+		return
+	}
+	t.DurationBegin, t.DurationEnd, err = w.times()
+	return
+}
+
+func (t TmplStartActivity) MarshalJSON() ([]byte, error) {
+	type plain TmplStartActivity
+	return json.Marshal(struct {
+		plain
+		*displayWindow
+	}{plain(t), newDisplayWindow(t.DurationBegin, t.DurationEnd)})
+}
+
+func (t *TmplStartActivity) UnmarshalJSON(data []byte) (err error) {
+	type plain TmplStartActivity
+	w := &displayWindow{}
+	if err = json.Unmarshal(data, &struct {
+		*plain
+		*displayWindow
+	}{(*plain)(t), w}); err != nil {
+		return
+	}
+	t.DurationBegin, t.DurationEnd, err = w.times()
+	return
+}
+
+func (t TmplTransmission) MarshalJSON() ([]byte, error) {
+	type plain TmplTransmission
+	return json.Marshal(struct {
+		plain
+		*displayWindow
+	}{plain(t), newDisplayWindow(t.DurationBegin, t.DurationEnd)})
+}
+
+func (t *TmplTransmission) UnmarshalJSON(data []byte) (err error) {
+	type plain TmplTransmission
+	w := &displayWindow{}
+	if err = json.Unmarshal(data, &struct {
+		*plain
+		*displayWindow
+	}{(*plain)(t), w}); err != nil {
+		return
+	}
+	t.DurationBegin, t.DurationEnd, err = w.times()
+	return
+}
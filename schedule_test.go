@@ -0,0 +1,88 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	utc := time.Date(2020, 3, 21, 6, 1, 3, 0, time.UTC)
+	if got := formatTime(utc); got != "2020-03-21 14:01:03" {
+		t.Fatalf("unexpected time %s", got)
+	}
+	if got := formatTime(time.Time{}); got != "" {
+		t.Fatalf("zero time should be empty, got %s", got)
+	}
+
+	parsed, err := parseTime("2020-03-21 14:01:03")
+	if err != nil || !parsed.Equal(utc) {
+		t.Fatalf("unexpected time %v, %v", parsed, err)
+	}
+}
+
+func TestTemplate_DurationJSON(t *testing.T) {
+	begin := time.Date(2020, 3, 21, 6, 0, 0, 0, time.UTC)
+	tmpl := TmplTransmission{TransmissionContent: "duration", DurationBegin: begin, DurationEnd: begin.Add(time.Hour)}
+
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"duration_begin":"2020-03-21 14:00:00"`) ||
+		!strings.Contains(string(data), `"duration_end":"2020-03-21 15:00:00"`) {
+		t.Fatalf("unexpected json %s", data)
+	}
+
+	var decoded TmplTransmission
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.DurationBegin.Equal(tmpl.DurationBegin) || !decoded.DurationEnd.Equal(tmpl.DurationEnd) ||
+		decoded.TransmissionContent != tmpl.TransmissionContent {
+		t.Fatalf("unexpected template %+v", decoded)
+	}
+
+	// 未设置时发送空字符串
+	data, _ = json.Marshal(TmplTransmission{TransmissionContent: "duration"})
+	if !strings.Contains(string(data), `"duration_begin":""`) {
+		t.Fatalf("unexpected json %s", data)
+	}
+}
+
+func TestPush_ValidateSchedule(t *testing.T) {
+	tests := []struct {
+		lead  time.Duration
+		valid bool
+	}{
+		{time.Hour, true},
+		{time.Minute, false},
+		{-time.Hour, false},
+		{8 * 24 * time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		push := &Push{Template: TmplTransmission{TransmissionContent: "schedule"}}
+		push.SetPushTime(time.Now().Add(tt.lead))
+		if err := push.Validate(); (err == nil) != tt.valid {
+			t.Errorf("lead %s: unexpected error %v", tt.lead, err)
+		}
+	}
+
+	push := &Push{Template: TmplTransmission{TransmissionContent: "schedule"}}
+	push.SetDuration(time.Now(), time.Now().Add(MaxDisplayWindow+time.Hour))
+	if err := push.Validate(); err == nil {
+		t.Fatal("expected error for a display window longer than MaxDisplayWindow")
+	}
+}
+
+func TestClient_QueryAppUser(t *testing.T) {
+	client := getClient(t)
+
+	result, stat, err := client.QueryAppUser(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(result, stat)
+}
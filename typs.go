@@ -1,5 +1,10 @@
 package GeTuiGo
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 const (
 	ResultOk                 = "ok"                   // 成功
 	ResultNoMsg              = "no_msg"               // 没有消息体
@@ -26,7 +31,51 @@ const (
 	ResultOtherError         = "other_error"          // 其他错误
 )
 
+// 通知栏消息布局样式，StyleXXX之一
 type IStyle interface {
+	GetStyleType() int // 样式类型，即 StyleTypeXXX 常量，序列化时写入type字段
+}
+
+// 样式类型
+const (
+	StyleTypeSystem = 0 // 系统样式
+	StyleTypeGeTui  = 1 // 个推样式
+	StyleTypeImage  = 4 // 纯图样式(背景图样式)
+	StyleTypeExt    = 6 // 展开通知样式
+)
+
+// 按type字段解析为对应的样式，空内容返回nil
+func unmarshalStyle(data []byte) (IStyle, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var head struct {
+		Type int `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	switch head.Type {
+	case StyleTypeSystem:
+		var style StyleSystem
+		err := json.Unmarshal(data, &style)
+		return style, err
+	case StyleTypeGeTui:
+		var style StyleGeTui
+		err := json.Unmarshal(data, &style)
+		return style, err
+	case StyleTypeImage:
+		var style StyleImage
+		err := json.Unmarshal(data, &style)
+		return style, err
+	case StyleTypeExt:
+		var style StyleExt
+		err := json.Unmarshal(data, &style)
+		return style, err
+	}
+	return nil, fmt.Errorf("getui: unknown style type %d", head.Type)
 }
 
 // 系统样式
@@ -54,6 +103,18 @@ type StyleSystem struct {
 	ChannelLevel int `json:"channel_level"`
 }
 
+func (s StyleSystem) GetStyleType() int {
+	return StyleTypeSystem
+}
+
+// 序列化时type字段固定为 StyleTypeSystem
+func (s StyleSystem) MarshalJSON() ([]byte, error) {
+	type plain StyleSystem
+	p := plain(s)
+	p.Type = StyleTypeSystem
+	return json.Marshal(p)
+}
+
 func NewStyleSystem() StyleSystem {
 	return StyleSystem{
 		Type:         0,
@@ -83,6 +144,18 @@ type StyleImage struct {
 	IsClearable bool   `json:"is_clearable"` // 通知是否可清除： true可清除，false不可清除。默认可清除,
 }
 
+func (s StyleImage) GetStyleType() int {
+	return StyleTypeImage
+}
+
+// 序列化时type字段固定为 StyleTypeImage
+func (s StyleImage) MarshalJSON() ([]byte, error) {
+	type plain StyleImage
+	p := plain(s)
+	p.Type = StyleTypeImage
+	return json.Marshal(p)
+}
+
 // 个推样式
 type StyleGeTui struct {
 	Type        int    `json:"type"`         // 必传: 系统样式=0,个推样式=1,纯图样式(背景图样式)=4,展开通知样式=6
@@ -96,6 +169,18 @@ type StyleGeTui struct {
 	NotifyId    int    `json:"notify_id"`    // 需要被覆盖的消息已经增加了notifyId字段，用于实现下发消息的覆盖。新的消息使用相同的notifyId下发。
 }
 
+func (s StyleGeTui) GetStyleType() int {
+	return StyleTypeGeTui
+}
+
+// 序列化时type字段固定为 StyleTypeGeTui
+func (s StyleGeTui) MarshalJSON() ([]byte, error) {
+	type plain StyleGeTui
+	p := plain(s)
+	p.Type = StyleTypeGeTui
+	return json.Marshal(p)
+}
+
 // 展开通知样式
 type StyleExt struct {
 	Type        int    `json:"type"`          // 必传: 系统样式=0,个推样式=1,纯图样式(背景图样式)=4,展开通知样式=6
@@ -123,17 +208,16 @@ type StyleExt struct {
 	ChannelLevel int `json:"channel_level"`
 }
 
-// apns推送消息, json串，当手机为ios，并且为离线的时候
-type ApnPushInfo struct {
-	Aps struct {
-		Alert            map[string]interface{} `json:"alert"`
-		AutoBadge        string                 `json:"auto_badge"`
-		Sound            string                 `json:"sound"`
-		ContentAvailable int                    `json:"content-available"`
-		Category         string                 `json:"category"`
-	} `json:"aps"`
-	Payload    string              `json:"payload"`
-	Multimedia []map[string]string `json:"multimedia"`
+func (s StyleExt) GetStyleType() int {
+	return StyleTypeExt
+}
+
+// 序列化时type字段固定为 StyleTypeExt
+func (s StyleExt) MarshalJSON() ([]byte, error) {
+	type plain StyleExt
+	p := plain(s)
+	p.Type = StyleTypeExt
+	return json.Marshal(p)
 }
 
 // Message 消息
@@ -146,7 +230,7 @@ type Message struct {
 }
 
 // 新消息
-//  msgType 请使用常量TypeXXX来设置
+//  msgType 请使用常量TypeXXX来设置，设置了Push.Template时以模板的类型为准
 func NewMessage(msgType string) *Message {
 	return &Message{
 		MsgType: msgType,
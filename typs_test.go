@@ -0,0 +1,63 @@
+package GeTuiGo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStyle_EnforceType(t *testing.T) {
+	styles := []IStyle{StyleSystem{Type: 6}, StyleGeTui{}, StyleImage{Type: 1}, StyleExt{}}
+	for _, style := range styles {
+		data, err := json.Marshal(style)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var head struct {
+			Type int `json:"type"`
+		}
+		json.Unmarshal(data, &head)
+		if head.Type != style.GetStyleType() {
+			t.Errorf("%T: expected type %d, got %d", style, style.GetStyleType(), head.Type)
+		}
+	}
+}
+
+func TestTemplate_StyleRoundTrip(t *testing.T) {
+	styles := []IStyle{
+		newTestStyle("system"),
+		StyleGeTui{Type: StyleTypeGeTui, Title: "getui", Text: "text", Logo: "push.png", IsRing: true},
+		StyleImage{Logo: "push.png", BannerUrl: "http://example.com/banner.png"},
+		StyleExt{Title: "ext", Text: "text", Logo: "push.png", BigStyle: 2, BigText: "long text"},
+	}
+
+	for _, style := range styles {
+		tmpl := TmplLink{Url: "http://example.com", Style: style}
+		data, err := json.Marshal(tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded TmplLink
+		if err = json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if reflect.TypeOf(decoded.Style) != reflect.TypeOf(style) {
+			t.Fatalf("expected %T, got %T", style, decoded.Style)
+		}
+
+		// 解析后再次序列化，结果应与原来一致
+		again, _ := json.Marshal(decoded)
+		if string(again) != string(data) {
+			t.Fatalf("round trip mismatch\n got: %s\nwant: %s", again, data)
+		}
+	}
+
+	var tmpl TmplNotification
+	err := json.Unmarshal([]byte(`{"style":{"type":3}}`), &tmpl)
+	if err == nil || !strings.Contains(err.Error(), "unknown style type") {
+		t.Fatalf("expected unknown style type error, got %v", err)
+	}
+}
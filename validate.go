@@ -0,0 +1,324 @@
+package GeTuiGo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// intent的最大长度，单位字节
+const maxIntentLength = 1000
+
+// tolist群推每次最多的目标数
+const maxPushListTargets = 1000
+
+// 批量单推每次最多的消息数
+const maxSinglePushBatch = 200
+
+// 单次查询推送结果最多的任务数
+const maxPushResultTasks = 100
+
+// 单次绑定别名最多的条数
+const maxBindAlias = 1000
+
+// 一个别名最多绑定的cid数
+const maxAliasCids = 10
+
+// 任务组名的最大长度，单位字符
+const maxTaskNameLength = 100
+
+// 一个字段的校验错误
+type FieldError struct {
+	Field   string // 字段路径，使用json字段名，如 notification.style.title
+	Message string // 错误原因
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// 校验错误列表，Validate 返回的错误，同时满足 errors.Is(err, ErrInvalidParam)
+type ValidationErrors []*FieldError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return "getui: invalid params: " + strings.Join(msgs, "; ")
+}
+
+func (errs ValidationErrors) Unwrap() error {
+	return ErrInvalidParam
+}
+
+// 收集校验错误
+type validator struct {
+	errs ValidationErrors
+}
+
+// 添加一个字段错误
+func (v *validator) add(field, format string, a ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+// 条件不成立时添加字段错误
+func (v *validator) check(ok bool, field, format string, a ...interface{}) {
+	if !ok {
+		v.add(field, format, a...)
+	}
+}
+
+// 必传的字符串字段
+func (v *validator) required(field, value string) {
+	v.check(value != "", field, "is required")
+}
+
+// 合并子对象的校验错误，字段路径加上前缀
+func (v *validator) nested(prefix string, err error) {
+	if err == nil {
+		return
+	}
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		v.add(prefix, "%v", err)
+		return
+	}
+	for _, e := range errs {
+		field := e.Field
+		switch {
+		case field == "":
+			field = prefix
+		case prefix != "":
+			field = prefix + "." + field
+		}
+		v.errs = append(v.errs, &FieldError{Field: field, Message: e.Message})
+	}
+}
+
+// 检查展示时间段，结束时间需晚于开始时间，时间段不超过 MaxDisplayWindow
+func (v *validator) duration(begin, end time.Time) {
+	if begin.IsZero() || end.IsZero() {
+		return
+	}
+	if !end.After(begin) {
+		v.add("duration_end", "must be after duration_begin")
+	} else if end.Sub(begin) > MaxDisplayWindow {
+		v.add("duration_end", "display window must not be longer than %s", MaxDisplayWindow)
+	}
+}
+
+// 检查定时下发时间，需在当前时间 MinScheduleLead 之后、MaxScheduleLead 之内
+func (v *validator) schedule(field string, pushTime time.Time) {
+	if pushTime.IsZero() {
+		return
+	}
+	lead := time.Until(pushTime)
+	v.check(lead >= MinScheduleLead && lead <= MaxScheduleLead, field,
+		"must be between %s and %s from now", MinScheduleLead, MaxScheduleLead)
+}
+
+// 检查任务组名，组名会出现在查询接口的路径中，只允许文字、数字、下划线和中划线
+func (v *validator) taskName(field, name string) {
+	if name == "" {
+		v.add(field, "is required")
+		return
+	}
+	v.check(utf8.RuneCountInString(name) <= maxTaskNameLength, field, "must not be longer than %d characters", maxTaskNameLength)
+	valid := strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	}) < 0
+	v.check(valid, field, "must only contain letters, digits, '_' and '-'")
+}
+
+// 通知渠道重要性0~4
+func (v *validator) channelLevel(level int) {
+	v.check(level >= 0 && level <= 4, "channel_level", "must be between 0 and 4")
+}
+
+// 检查样式，样式实现了Validate时调用
+func (v *validator) style(field string, style IStyle) {
+	if s, ok := style.(interface{ Validate() error }); ok {
+		v.nested(field, s.Validate())
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// 检查系统样式
+func (s StyleSystem) Validate() error {
+	var v validator
+	v.check(s.Type == StyleTypeSystem, "type", "must be %d for system style", StyleTypeSystem)
+	v.required("text", s.Text)
+	v.required("title", s.Title)
+	v.required("logo", s.Logo)
+	v.check(s.BigStyle == 1 || s.BigStyle == 2, "big_style", "must be 1 or 2 for system style")
+	v.channelLevel(s.ChannelLevel)
+	return v.err()
+}
+
+// 检查纯图样式
+func (s StyleImage) Validate() error {
+	var v validator
+	v.check(s.Type == 0 || s.Type == StyleTypeImage, "type", "must be %d for image style", StyleTypeImage)
+	v.required("logo", s.Logo)
+	v.required("banner_url", s.BannerUrl)
+	return v.err()
+}
+
+// 检查个推样式
+func (s StyleGeTui) Validate() error {
+	var v validator
+	v.check(s.Type == 0 || s.Type == StyleTypeGeTui, "type", "must be %d for getui style", StyleTypeGeTui)
+	v.required("text", s.Text)
+	v.required("title", s.Title)
+	v.required("logo", s.Logo)
+	return v.err()
+}
+
+// 检查展开通知样式
+func (s StyleExt) Validate() error {
+	var v validator
+	v.check(s.Type == 0 || s.Type == StyleTypeExt, "type", "must be %d for ext style", StyleTypeExt)
+	v.required("text", s.Text)
+	v.required("title", s.Title)
+	v.required("logo", s.Logo)
+	v.required("banner_url", s.BannerUrl)
+	v.channelLevel(s.ChannelLevel)
+	return v.err()
+}
+
+// 检查点开通知打开应用模板
+func (t TmplNotification) Validate() error {
+	var v validator
+	v.duration(t.DurationBegin, t.DurationEnd)
+	v.style("style", t.Style)
+	return v.err()
+}
+
+// 检查点开通知打开网页模板
+func (t TmplLink) Validate() error {
+	var v validator
+	v.required("url", t.Url)
+	v.duration(t.DurationBegin, t.DurationEnd)
+	v.style("style", t.Style)
+	return v.err()
+}
+
+// 检查点击通知弹窗下载模板
+func (t TmplNotifyPopLoad) Validate() error {
+	var v validator
+	v.required("notyicon", t.NotifyIcon)
+	v.required("notytitle", t.NotifyTitle)
+	v.required("notycontent", t.NotifyContent)
+	v.required("poptitle", t.PopTitle)
+	v.required("popcontent", t.PopContent)
+	v.required("popimage", t.PopImage)
+	v.required("popbutton_1", t.PopButton1)
+	v.required("popbutton_2", t.PopButton2)
+	v.required("loadurl", t.LoadUrl)
+	v.duration(t.DurationBegin, t.DurationEnd)
+	return v.err()
+}
+
+// 检查点开通知打开应用内特定页面模板
+func (t TmplStartActivity) Validate() error {
+	var v validator
+	v.duration(t.DurationBegin, t.DurationEnd)
+
+	if t.Intent == "" {
+		v.add("intent", "is required")
+	} else {
+		v.check(len(t.Intent) < maxIntentLength, "intent", "must be shorter than %d bytes", maxIntentLength)
+		if _, err := ParseIntent(t.Intent); err != nil {
+			v.add("intent", "%s", strings.TrimPrefix(err.Error(), "getui: "))
+		}
+	}
+	return v.err()
+}
+
+// 检查透传消息模板
+func (t TmplTransmission) Validate() error {
+	var v validator
+	v.required("transmission_content", t.TransmissionContent)
+	v.duration(t.DurationBegin, t.DurationEnd)
+	return v.err()
+}
+
+// 检查推送消息，返回的错误为 ValidationErrors
+//  SinglePush、PushToApp 等接口发送前会自动检查，可以使用 WithoutValidation 关闭
+func (push *Push) Validate() error {
+	var v validator
+	if push.Template == nil {
+		v.add("template", "is required")
+	} else if t, ok := push.Template.(interface{ Validate() error }); ok {
+		v.nested(push.Template.GetMsgType(), t.Validate())
+	}
+
+	if m := push.Message; m != nil {
+		if m.IsOffline {
+			v.check(m.OfflineExpireTime > 0 && m.OfflineExpireTime <= int(maxOfflineExpire/time.Millisecond),
+				"message.offline_expire_time", "must be between 1 and %d ms", int(maxOfflineExpire/time.Millisecond))
+		}
+		v.check(m.PushNetworkType == 0 || m.PushNetworkType == 1, "message.push_network_type", "must be 0 or 1")
+	}
+
+	v.check(push.Cid == "" || push.Alias == "", "alias", "cid and alias are mutually exclusive")
+	for i, cond := range push.conditions {
+		v.nested(fmt.Sprintf("condition[%d]", i), cond.Validate())
+	}
+	v.check(push.speed >= 0, "speed", "must not be negative")
+	if push.taskName != "" {
+		v.taskName("task_name", push.taskName)
+	}
+	v.duration(push.durationBegin, push.durationEnd)
+	v.schedule("push_time", push.pushTime)
+	if push.PushInfo != nil {
+		v.nested("push_info", push.PushInfo.Validate())
+	}
+	return v.err()
+}
+
+// 检查v2单推的目标，只能有一个cid或别名
+//  field	cid或alias
+func validateSingleV2(field string, targets []string) error {
+	var v validator
+	v.check(len(targets) == 1, "audience."+field, "must contain exactly one %s", field)
+	return v.err()
+}
+
+// 检查v2批量单推的消息
+func validateSinglePushBatchV2(reqList []*PushRequestV2) error {
+	var v validator
+	v.check(len(reqList) <= maxSinglePushBatch, "msg_list", "must not contain more than %d messages", maxSinglePushBatch)
+	for i, req := range reqList {
+		v.nested(fmt.Sprintf("msg_list[%d]", i), validateSingleV2("cid", req.Audience.cids()))
+	}
+	return v.err()
+}
+
+// 检查v2 tolist群推参数
+func validatePushListV2(taskId string, cidList []string) error {
+	var v validator
+	v.required("taskid", taskId)
+	v.check(len(cidList) > 0, "audience.cid", "is required")
+	v.check(len(cidList) <= maxPushListTargets, "audience.cid", "must not contain more than %d cids", maxPushListTargets)
+	return v.err()
+}
+
+// 检查tolist群推参数
+func (p *PushList) Validate() error {
+	var v validator
+	v.required("taskid", p.TaskId)
+	v.check(len(p.Cid) > 0 || len(p.Alias) > 0, "cid", "cid or alias is required")
+	v.check(len(p.Cid) <= maxPushListTargets, "cid", "must not contain more than %d cids", maxPushListTargets)
+	v.check(len(p.Alias) <= maxPushListTargets, "alias", "must not contain more than %d aliases", maxPushListTargets)
+	return v.err()
+}
@@ -0,0 +1,118 @@
+package GeTuiGo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 返回校验错误的字段路径
+func errorFields(t *testing.T, err error) []string {
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected ErrInvalidParam, got %v", err)
+	}
+
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+func TestPush_Validate(t *testing.T) {
+	now := time.Now()
+	style := NewStyleSystem()
+	style.BigStyle = 3
+	style.ChannelLevel = 5
+	push := &Push{
+		Template: TmplNotification{Style: style, DurationBegin: now, DurationEnd: now.Add(-time.Hour)},
+		Message:  &Message{IsOffline: true},
+		Cid:      "cid",
+		Alias:    "alias",
+	}
+
+	got := strings.Join(errorFields(t, push.Validate()), ",")
+	want := "notification.duration_end,notification.style.text,notification.style.title,notification.style.logo," +
+		"notification.style.big_style,notification.style.channel_level,message.offline_expire_time,alias"
+	if got != want {
+		t.Fatalf("unexpected fields\n got: %s\nwant: %s", got, want)
+	}
+
+	if err := (&Push{Template: TmplNotification{Style: newTestStyle("Validate")}}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTmplStartActivity_Validate(t *testing.T) {
+	tests := []struct {
+		intent string
+		valid  bool
+	}{
+		{"intent:#Intent;component=com.example/.MainActivity;end", true},
+		{"", false},
+		{"#Intent;component=com.example/.MainActivity;end", false},
+		{"intent:#Intent;component=com.example/.MainActivity", false},
+		{"intent:#Intent;S.p=" + strings.Repeat("x", maxIntentLength) + ";end", false},
+	}
+
+	for _, tt := range tests {
+		err := TmplStartActivity{Intent: tt.intent}.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("intent %v: unexpected error %v", tt.intent, err)
+		}
+	}
+}
+
+func TestClient_Validation(t *testing.T) {
+	client := getClient(t)
+
+	push := &Push{Template: TmplTransmission{}, Cid: testCid}
+	if _, err := client.SinglePush(push); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := client.PushList(&PushList{}); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	// 关闭校验后直接发送
+	client, err := NewClient("8pBAMeizL7AToQifGbUqn1", "aj3YmXBs5l7Vj9x4UvFyiA", "kHUVG5uojo9rVJ4XrZ0yx2",
+		WithBaseURL(fakeServer.BaseURL()), WithoutValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SinglePush(push); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_ValidateSingleTarget(t *testing.T) {
+	client := getClient(t)
+
+	push := &Push{Template: TmplTransmission{TransmissionContent: "no target"}}
+	before := fakeServer.RequestCount("push_single")
+	_, err := client.SinglePush(push)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "cid" {
+		t.Fatalf("expected cid validation error, got %v", err)
+	}
+	if fakeServer.RequestCount("push_single") != before {
+		t.Fatal("push without a target should not be sent")
+	}
+	if _, err := client.SinglePushBatch([]*Push{push}, false); !errors.Is(err, ErrInvalidParam) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	// 群推不需要目标
+	if err := push.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	push.Alias = "alias"
+	if err := client.validateSingle(push); err != nil {
+		t.Fatal(err)
+	}
+}
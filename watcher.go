@@ -0,0 +1,266 @@
+package GeTuiGo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReportWatcher 的默认设置
+const (
+	defaultWatchInterval    = 5 * time.Second
+	defaultWatchMaxInterval = 5 * time.Minute
+	defaultWatchSettleAfter = 3
+	defaultWatchBatchSize   = maxPushResultTasks
+)
+
+// 推送结果变化事件
+type ReportEvent struct {
+	TaskId   string           // 任务号
+	Previous PushResultDetail // 上次查询的结果，第一次查询时为零值
+	Current  PushResultDetail // 本次查询的结果
+	Settled  bool             // 数据已稳定，之后不再查询该任务
+}
+
+// 推送结果轮询器，分批查询一组任务的推送结果，数据变化时发出事件，所有任务稳定或到达截止时间后停止
+//  数据变化后轮询间隔恢复为Interval，没有变化时逐次加倍，最长为MaxInterval
+//  还查不到推送结果的任务（报告未生成或任务号错误）不会稳定，一直轮询到截止时间，可以用 Missing 获取
+//  示例：
+//  w := client.NewReportWatcher(taskId)
+//  w.OnChange = func(e ReportEvent) { ... }
+//  err := w.Run(ctx)
+type ReportWatcher struct {
+	Interval    time.Duration     // 初始轮询间隔，默认5秒
+	MaxInterval time.Duration     // 最长轮询间隔，默认5分钟
+	SettleAfter int               // 连续几次查询数据没有变化时视为稳定，默认3
+	Deadline    time.Time         // 截止时间，零值表示不限制
+	BatchSize   int               // 每次请求查询的任务数，默认100
+	OnChange    func(ReportEvent) // 数据变化和稳定时调用，使用 Run 运行时生效
+
+	client *Client
+	mu     sync.Mutex
+	tasks  []*watchedTask
+	err    error // Watch 运行结束的原因
+}
+
+// 轮询中的任务
+type watchedTask struct {
+	taskId    string
+	last      PushResultDetail
+	unchanged int // 连续没有变化的次数
+	settled   bool
+	found     bool // 查询到过推送结果
+}
+
+// 创建推送结果轮询器
+//  taskIds	要查询的任务号，之后可以用 Add 添加
+func (c *Client) NewReportWatcher(taskIds ...string) *ReportWatcher {
+	w := &ReportWatcher{client: c}
+	w.Add(taskIds...)
+	return w
+}
+
+// 添加要查询的任务，运行中也可以添加，已添加的任务号忽略
+func (w *ReportWatcher) Add(taskIds ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, taskId := range taskIds {
+		exists := false
+		for _, task := range w.tasks {
+			exists = exists || task.taskId == taskId
+		}
+		if !exists {
+			w.tasks = append(w.tasks, &watchedTask{taskId: taskId})
+		}
+	}
+}
+
+// 还没有稳定的任务号
+func (w *ReportWatcher) Pending() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var taskIds []string
+	for _, task := range w.tasks {
+		if !task.settled {
+			taskIds = append(taskIds, task.taskId)
+		}
+	}
+	return taskIds
+}
+
+// 还没有查询到推送结果的任务号
+func (w *ReportWatcher) Missing() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var taskIds []string
+	for _, task := range w.tasks {
+		if !task.found {
+			taskIds = append(taskIds, task.taskId)
+		}
+	}
+	return taskIds
+}
+
+// 运行轮询，事件通过OnChange回调，阻塞到所有任务稳定后返回nil
+//  到达Deadline时返回 context.DeadlineExceeded，查询失败时返回对应的错误，ctx结束时返回ctx.Err()
+func (w *ReportWatcher) Run(ctx context.Context) error {
+	return w.run(ctx, func(e ReportEvent) error {
+		if w.OnChange != nil {
+			w.OnChange(e)
+		}
+		return nil
+	})
+}
+
+// 在后台运行轮询，事件通过返回的channel发出，结束后关闭channel，结束的原因使用 Err 获取
+//  调用方需要持续读取channel，否则轮询会阻塞
+func (w *ReportWatcher) Watch(ctx context.Context) <-chan ReportEvent {
+	events := make(chan ReportEvent, 16)
+	go func() {
+		defer close(events)
+		err := w.run(ctx, func(e ReportEvent) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	}()
+	return events
+}
+
+// Watch 结束的原因，含义同 Run 的返回值，运行中返回nil
+func (w *ReportWatcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *ReportWatcher) run(ctx context.Context, emit func(ReportEvent) error) error {
+	if !w.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, w.Deadline)
+		defer cancel()
+	}
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	maxInterval := w.MaxInterval
+	if maxInterval < interval {
+		maxInterval = defaultWatchMaxInterval
+		if maxInterval < interval {
+			maxInterval = interval
+		}
+	}
+
+	wait := interval
+	for {
+		changed, err := w.poll(ctx, emit)
+		if err != nil {
+			if ctx.Err() != nil {
+				// 查询被截止时间或ctx打断
+				return ctx.Err()
+			}
+			return err
+		}
+		if len(w.Pending()) == 0 {
+			return nil
+		}
+
+		if changed {
+			wait = interval
+		} else if wait *= 2; wait > maxInterval {
+			wait = maxInterval
+		}
+		if err = sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// 分批查询未稳定的任务，返回是否有数据变化
+func (w *ReportWatcher) poll(ctx context.Context, emit func(ReportEvent) error) (changed bool, err error) {
+	w.mu.Lock()
+	var pending []*watchedTask
+	for _, task := range w.tasks {
+		if !task.settled {
+			pending = append(pending, task)
+		}
+	}
+	w.mu.Unlock()
+
+	batchSize := w.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultWatchBatchSize
+	}
+	settleAfter := w.SettleAfter
+	if settleAfter <= 0 {
+		settleAfter = defaultWatchSettleAfter
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+		taskIds := make([]string, len(batch))
+		for i, task := range batch {
+			taskIds[i] = task.taskId
+		}
+
+		_, details, err := w.client.GetPushResultContext(ctx, taskIds)
+		if err != nil {
+			return changed, err
+		}
+		byTaskId := make(map[string]PushResultDetail, len(details))
+		for _, detail := range details {
+			byTaskId[detail.TaskId] = detail
+		}
+
+		for _, task := range batch {
+			current, ok := byTaskId[task.taskId]
+			w.mu.Lock()
+			if !ok && !task.found {
+				// 从未查到的任务不计入稳定次数
+				w.mu.Unlock()
+				continue
+			}
+			if !ok {
+				// 之前查到过的任务保持上次的结果
+				current = task.last
+			}
+			task.found = true
+			event := ReportEvent{TaskId: task.taskId, Previous: task.last, Current: current}
+
+			if current != task.last {
+				task.unchanged = 0
+				task.last = current
+			} else {
+				task.unchanged++
+			}
+			task.settled = task.unchanged >= settleAfter
+			event.Settled = task.settled
+			w.mu.Unlock()
+
+			if event.Current == event.Previous && !event.Settled {
+				continue
+			}
+			changed = changed || event.Current != event.Previous
+			if err = emit(event); err != nil {
+				return changed, err
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,96 @@
+package GeTuiGo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func pushTestTask(t *testing.T, client *Client) string {
+	result, err := client.SinglePush(&Push{Template: TmplTransmission{TransmissionContent: "watcher"}, Cid: testCid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.TaskId
+}
+
+func TestReportWatcher_Run(t *testing.T) {
+	client := getClient(t)
+	taskIds := []string{pushTestTask(t, client), pushTestTask(t, client)}
+
+	w := client.NewReportWatcher(taskIds...)
+	w.Interval = time.Millisecond
+	w.SettleAfter = 2
+	w.BatchSize = 1
+	var changes, settled []ReportEvent
+	w.OnChange = func(e ReportEvent) {
+		if e.Settled {
+			settled = append(settled, e)
+		} else {
+			changes = append(changes, e)
+		}
+	}
+
+	before := fakeServer.RequestCount("push_result")
+	if err := w.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].TaskId != taskIds[0] || changes[0].Current.PushNum != 1 || changes[0].Previous.PushNum != 0 {
+		t.Fatalf("unexpected change events %+v", changes)
+	}
+	if len(settled) != 2 || len(w.Pending()) != 0 {
+		t.Fatalf("unexpected settled events %+v", settled)
+	}
+	// 每批一个任务，查询3次后稳定
+	if n := fakeServer.RequestCount("push_result") - before; n != 6 {
+		t.Fatalf("expected 6 push_result requests, got %d", n)
+	}
+}
+
+func TestReportWatcher_Watch(t *testing.T) {
+	client := getClient(t)
+
+	w := client.NewReportWatcher(pushTestTask(t, client))
+	w.Interval = time.Millisecond
+	w.SettleAfter = 1000
+	w.Deadline = time.Now().Add(50 * time.Millisecond)
+
+	var events []ReportEvent
+	for e := range w.Watch(context.Background()) {
+		events = append(events, e)
+	}
+	if len(events) != 1 || events[0].Settled {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	if w.Err() != context.DeadlineExceeded || len(w.Pending()) != 1 {
+		t.Fatalf("expected deadline exceeded, got %v", w.Err())
+	}
+}
+
+func TestReportWatcher_Missing(t *testing.T) {
+	client := getClient(t)
+
+	taskId := pushTestTask(t, client)
+	w := client.NewReportWatcher(taskId, "unknown-task")
+	w.Interval = time.Millisecond
+	w.MaxInterval = time.Millisecond
+	w.SettleAfter = 2
+	w.Deadline = time.Now().Add(100 * time.Millisecond)
+	var settled []string
+	w.OnChange = func(e ReportEvent) {
+		if e.Settled {
+			settled = append(settled, e.TaskId)
+		}
+	}
+
+	// 查不到的任务不会被当作已完成
+	if err := w.Run(context.Background()); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if len(settled) != 1 || settled[0] != taskId {
+		t.Fatalf("unexpected settled tasks %v", settled)
+	}
+	if pending, missing := w.Pending(), w.Missing(); len(pending) != 1 || pending[0] != "unknown-task" || len(missing) != 1 || missing[0] != "unknown-task" {
+		t.Fatalf("unexpected pending %v, missing %v", pending, missing)
+	}
+}